@@ -0,0 +1,259 @@
+package gomap
+
+import (
+	"bytes"
+	"crypto"
+	_ "crypto/md5" // register the hash functions for Hash
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// ErrInvalidNumber ...
+var ErrInvalidNumber = errors.New("invalid number")
+
+//CanonicalJSON transfer map to the canonical JSON of RFC 8785,
+// the keys are sorted, the numbers are written as IEEE 754 double and there is no whitespace.
+// It returns ErrInvalidNumber for NaN, Inf and the integers which a double can not hold exactly
+func (m Map) CanonicalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeCanonical(&buf, m); err != nil {
+		return nil, fmt.Errorf("map to canonical json error:%w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Hash returns the hex digest of the canonical JSON of map, it is stable whatever the insertion order
+// and whether the children are Map or map[string]interface{}
+func (m Map) Hash(alg crypto.Hash) (string, error) {
+	if !alg.Available() {
+		return "", fmt.Errorf("%w: hash %d", ErrUnsupportedType, alg)
+	}
+	b, err := m.CanonicalJSON()
+	if err != nil {
+		return "", err
+	}
+	h := alg.New()
+	h.Write(b)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func writeCanonical(buf *bytes.Buffer, value interface{}) error {
+	if m, b := toMap(value); b {
+		return writeCanonicalMap(buf, m)
+	}
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case string:
+		writeCanonicalString(buf, v)
+	case json.Number:
+		return writeCanonicalJSONNumber(buf, v)
+	case float64:
+		return writeCanonicalNumber(buf, v)
+	case float32:
+		return writeCanonicalNumber(buf, float64(v))
+	case int:
+		return writeCanonicalInt(buf, int64(v))
+	case int8:
+		return writeCanonicalInt(buf, int64(v))
+	case int16:
+		return writeCanonicalInt(buf, int64(v))
+	case int32:
+		return writeCanonicalInt(buf, int64(v))
+	case int64:
+		return writeCanonicalInt(buf, v)
+	case uint:
+		return writeCanonicalUint(buf, uint64(v))
+	case uint8:
+		return writeCanonicalUint(buf, uint64(v))
+	case uint16:
+		return writeCanonicalUint(buf, uint64(v))
+	case uint32:
+		return writeCanonicalUint(buf, uint64(v))
+	case uint64:
+		return writeCanonicalUint(buf, v)
+	case time.Time:
+		writeCanonicalString(buf, v.Format(time.RFC3339Nano))
+	default:
+		if arr, b := toArray(value); b {
+			buf.WriteByte('[')
+			for i := range arr {
+				if i > 0 {
+					buf.WriteByte(',')
+				}
+				if err := writeCanonical(buf, arr[i]); err != nil {
+					return err
+				}
+			}
+			buf.WriteByte(']')
+			return nil
+		}
+		// the other values are written as their JSON
+		b, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+		var v0 interface{}
+		if err := dec.Decode(&v0); err != nil {
+			return err
+		}
+		return writeCanonical(buf, v0)
+	}
+	return nil
+}
+
+func writeCanonicalMap(buf *bytes.Buffer, m Map) error {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	// the keys are sorted by their UTF-16 code units
+	sort.Slice(keys, func(i, j int) bool {
+		return lessUTF16(keys[i], keys[j])
+	})
+	buf.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		writeCanonicalString(buf, k)
+		buf.WriteByte(':')
+		if err := writeCanonical(buf, m[k]); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+func lessUTF16(a, b string) bool {
+	ua, ub := utf16.Encode([]rune(a)), utf16.Encode([]rune(b))
+	for i := 0; i < len(ua) && i < len(ub); i++ {
+		if ua[i] != ub[i] {
+			return ua[i] < ub[i]
+		}
+	}
+	return len(ua) < len(ub)
+}
+
+// writeCanonicalInt writes the integer as double, it returns ErrInvalidNumber if the double can not hold it exactly,
+// so the different integers above 2^53 never have the same canonical JSON
+func writeCanonicalInt(buf *bytes.Buffer, v int64) error {
+	f := float64(v)
+	// float64(math.MaxInt64) is 2^63 which overflows int64
+	if f >= math.MaxInt64 || int64(f) != v {
+		return fmt.Errorf("%w: %d is not exact as double", ErrInvalidNumber, v)
+	}
+	return writeCanonicalNumber(buf, f)
+}
+
+// writeCanonicalUint is the same as writeCanonicalInt for the unsigned integer
+func writeCanonicalUint(buf *bytes.Buffer, v uint64) error {
+	f := float64(v)
+	if f >= math.MaxUint64 || uint64(f) != v {
+		return fmt.Errorf("%w: %d is not exact as double", ErrInvalidNumber, v)
+	}
+	return writeCanonicalNumber(buf, f)
+}
+
+// writeCanonicalJSONNumber writes the integral json.Number like writeCanonicalInt and the others as double
+func writeCanonicalJSONNumber(buf *bytes.Buffer, v json.Number) error {
+	if i, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+		return writeCanonicalInt(buf, i)
+	}
+	if u, err := strconv.ParseUint(string(v), 10, 64); err == nil {
+		return writeCanonicalUint(buf, u)
+	}
+	f, err := v.Float64()
+	if err != nil || !strings.ContainsAny(string(v), ".eE") {
+		// the integers out of the range of int64 and uint64 are not exact
+		return fmt.Errorf("%w: %s", ErrInvalidNumber, v)
+	}
+	return writeCanonicalNumber(buf, f)
+}
+
+// writeCanonicalNumber writes the number like the ECMAScript Number.prototype.toString
+func writeCanonicalNumber(buf *bytes.Buffer, f float64) error {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Errorf("%w: %v", ErrInvalidNumber, f)
+	}
+	if f == 0 {
+		buf.WriteByte('0')
+		return nil
+	}
+	format := byte('f')
+	if abs := math.Abs(f); abs < 1e-6 || abs >= 1e21 {
+		format = 'e'
+	}
+	b := strconv.AppendFloat(nil, f, format, -1, 64)
+	if format == 'e' {
+		// clean up e-09 to e-9
+		n := len(b)
+		if n >= 4 && b[n-4] == 'e' && b[n-3] == '-' && b[n-2] == '0' {
+			b[n-2] = b[n-1]
+			b = b[:n-1]
+		}
+	}
+	buf.Write(b)
+	return nil
+}
+
+// writeCanonicalString escapes only the quotation mark, the reverse solidus and the control characters
+func writeCanonicalString(buf *bytes.Buffer, s string) {
+	const hexDigits = "0123456789abcdef"
+	buf.WriteByte('"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c >= utf8.RuneSelf {
+			r, size := utf8.DecodeRuneInString(s[i:])
+			if r == utf8.RuneError && size == 1 {
+				buf.WriteString("�")
+			} else {
+				buf.WriteString(s[i : i+size])
+			}
+			i += size
+			continue
+		}
+		switch c {
+		case '"', '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if c < 0x20 {
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hexDigits[c>>4])
+				buf.WriteByte(hexDigits[c&0xf])
+			} else {
+				buf.WriteByte(c)
+			}
+		}
+		i++
+	}
+	buf.WriteByte('"')
+}
@@ -0,0 +1,138 @@
+package gomap
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+// the number vectors of RFC 8785 Appendix B
+func Test_writeCanonicalNumber(t *testing.T) {
+	tests := []struct {
+		name    string
+		bits    uint64
+		want    string
+		wantErr error
+	}{
+		{name: "zero", bits: 0x0000000000000000, want: "0"},
+		{name: "minus zero", bits: 0x8000000000000000, want: "0"},
+		{name: "min pos number", bits: 0x0000000000000001, want: "5e-324"},
+		{name: "min neg number", bits: 0x8000000000000001, want: "-5e-324"},
+		{name: "max pos number", bits: 0x7fefffffffffffff, want: "1.7976931348623157e+308"},
+		{name: "max neg number", bits: 0xffefffffffffffff, want: "-1.7976931348623157e+308"},
+		{name: "max pos int", bits: 0x4340000000000000, want: "9007199254740992"},
+		{name: "max neg int", bits: 0xc340000000000000, want: "-9007199254740992"},
+		{name: "~2**68", bits: 0x4430000000000000, want: "295147905179352830000"},
+		{name: "NaN", bits: 0x7fffffffffffffff, wantErr: ErrInvalidNumber},
+		{name: "Infinity", bits: 0x7ff0000000000000, wantErr: ErrInvalidNumber},
+		{name: "1e+23 minus", bits: 0x44b52d02c7e14af5, want: "9.999999999999997e+22"},
+		{name: "1e+23", bits: 0x44b52d02c7e14af6, want: "1e+23"},
+		{name: "1e+23 plus", bits: 0x44b52d02c7e14af7, want: "1.0000000000000001e+23"},
+		{name: "1e+21 minus 2", bits: 0x444b1ae4d6e2ef4e, want: "999999999999999700000"},
+		{name: "1e+21 minus", bits: 0x444b1ae4d6e2ef4f, want: "999999999999999900000"},
+		{name: "1e+21", bits: 0x444b1ae4d6e2ef50, want: "1e+21"},
+		{name: "1e-6 minus", bits: 0x3eb0c6f7a0b5ed8c, want: "9.999999999999997e-7"},
+		{name: "1e-6", bits: 0x3eb0c6f7a0b5ed8d, want: "0.000001"},
+		{name: "rounding 1", bits: 0x41b3de4355555553, want: "333333333.3333332"},
+		{name: "rounding 2", bits: 0x41b3de4355555554, want: "333333333.33333325"},
+		{name: "rounding 3", bits: 0x41b3de4355555555, want: "333333333.3333333"},
+		{name: "rounding 4", bits: 0x41b3de4355555556, want: "333333333.3333334"},
+		{name: "rounding 5", bits: 0x41b3de4355555557, want: "333333333.33333343"},
+		{name: "small negative", bits: 0xbecbf647612f3696, want: "-0.0000033333333333333333"},
+		{name: "large fraction", bits: 0x43143ff3c1cb0959, want: "1424953923781206.2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := writeCanonicalNumber(&buf, math.Float64frombits(tt.bits))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("writeCanonicalNumber() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("writeCanonicalNumber() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("writeCanonicalNumber() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMap_CanonicalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       Map
+		want    string
+		wantErr error
+	}{
+		{
+			name: "sorting of RFC 8785",
+			m: Map{"€": "Euro Sign", "\r": "Carriage Return", "דּ": "Hebrew Letter Dalet With Dagesh",
+				"1": "One", "\U0001f600": "Emoji: Grinning Face", "\u0080": "Control",
+				"ö": "Latin Small Letter O With Diaeresis"},
+			want: `{"\r":"Carriage Return","1":"One","` + "\u0080" + `":"Control","` + "ö" +
+				`":"Latin Small Letter O With Diaeresis","` + "€" + `":"Euro Sign","` + "\U0001f600" +
+				`":"Emoji: Grinning Face","` + "דּ" + `":"Hebrew Letter Dalet With Dagesh"}`,
+		},
+		{
+			name: "escaping",
+			m:    Map{"s": "\"\\/\b\f\n\r\t\x1f<>& "},
+			want: `{"s":"\"\\/\b\f\n\r\t\u001f<>&` + " " + `"}`,
+		},
+		{
+			name: "nested",
+			m:    Map{"b": []interface{}{1, true, nil}, "a": map[string]interface{}{"y": 1.5, "x": "z"}},
+			want: `{"a":{"x":"z","y":1.5},"b":[1,true,null]}`,
+		},
+		{name: "int64 2^53", m: Map{"id": int64(1 << 53)}, want: `{"id":9007199254740992}`},
+		{name: "int64 exact above 2^53", m: Map{"id": int64(1<<53 + 2)}, want: `{"id":9007199254740994}`},
+		{name: "int64 2^53+1", m: Map{"id": int64(1<<53 + 1)}, wantErr: ErrInvalidNumber},
+		{name: "max int64", m: Map{"id": int64(math.MaxInt64)}, wantErr: ErrInvalidNumber},
+		{name: "min int64", m: Map{"id": int64(math.MinInt64)}, want: `{"id":-9223372036854776000}`},
+		{name: "max uint64", m: Map{"id": uint64(math.MaxUint64)}, wantErr: ErrInvalidNumber},
+		{name: "json.Number integer", m: Map{"id": json.Number("9007199254740993")}, wantErr: ErrInvalidNumber},
+		{name: "json.Number out of range", m: Map{"id": json.Number("123456789012345678901234")}, wantErr: ErrInvalidNumber},
+		{name: "json.Number float", m: Map{"id": json.Number("1.50")}, want: `{"id":1.5}`},
+		{name: "NaN", m: Map{"f": math.NaN()}, wantErr: ErrInvalidNumber},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.CanonicalJSON()
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("CanonicalJSON() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CanonicalJSON() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("CanonicalJSON() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMap_Hash(t *testing.T) {
+	a, err := Map{"a": 1, "b": Map{"c": "d"}}.Hash(crypto.SHA256)
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	b, err := Map{"b": map[string]interface{}{"c": "d"}, "a": 1.0}.Hash(crypto.SHA256)
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	if a != b {
+		t.Errorf("Hash() = %v and %v, want the same", a, b)
+	}
+	if _, err := (Map{"id": int64(1<<53 + 1)}).Hash(crypto.SHA256); !errors.Is(err, ErrInvalidNumber) {
+		t.Errorf("Hash() error = %v, want %v", err, ErrInvalidNumber)
+	}
+}
@@ -0,0 +1,440 @@
+package gomap
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+)
+
+// the major types of CBOR
+const (
+	cborUint byte = iota << 5
+	cborNegInt
+	cborBytes
+	cborText
+	cborArray
+	cborMap
+	cborTag
+	cborSimple
+)
+
+const (
+	cborTagTimeString = 0
+	cborTagTimeEpoch  = 1
+	cborIndefinite    = 31
+	cborBreak         = 0xff
+)
+
+//ToCBOR transfer map to CBOR, time.Time is written as RFC 3339 string with tag 0
+func (m Map) ToCBOR() ([]byte, error) {
+	e := &cborEncoder{}
+	if err := e.encode(m); err != nil {
+		return nil, fmt.Errorf("map to cbor error:%w", err)
+	}
+	return e.buf, nil
+}
+
+//ParseCBOR parse CBOR bytes to map, the integers are decoded to int64
+// (uint64 if it overflows int64), the arrays of maps to []Map and the times of tag 0 and 1 to time.Time
+func (m Map) ParseCBOR(b []byte) error {
+	d := &cborDecoder{buf: b}
+	v, err := d.decode()
+	if err != nil {
+		return fmt.Errorf("cbor to map error:%w", err)
+	}
+	mp, ok := v.(Map)
+	if !ok {
+		return fmt.Errorf("cbor to map error:%w", ErrUnsupportedType)
+	}
+	for k := range mp {
+		m[k] = mp[k]
+	}
+	return nil
+}
+
+type cborEncoder struct {
+	buf []byte
+}
+
+func (e *cborEncoder) head(major byte, n uint64) {
+	switch {
+	case n < 24:
+		e.buf = append(e.buf, major|byte(n))
+	case n <= math.MaxUint8:
+		e.buf = append(e.buf, major|24, byte(n))
+	case n <= math.MaxUint16:
+		e.buf = append(e.buf, major|25, byte(n>>8), byte(n))
+	case n <= math.MaxUint32:
+		e.buf = appendUint32(append(e.buf, major|26), uint32(n))
+	default:
+		e.buf = appendUint64(append(e.buf, major|27), n)
+	}
+}
+
+func (e *cborEncoder) encodeInt(v int64) {
+	if v >= 0 {
+		e.head(cborUint, uint64(v))
+		return
+	}
+	e.head(cborNegInt, uint64(-1-v))
+}
+
+func (e *cborEncoder) encodeMap(m map[string]interface{}) error {
+	e.head(cborMap, uint64(len(m)))
+	for _, k := range Map(m).SortKeys() {
+		e.head(cborText, uint64(len(k)))
+		e.buf = append(e.buf, k...)
+		if err := e.encode(m[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *cborEncoder) encode(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		e.buf = append(e.buf, cborSimple|22)
+	case bool:
+		if v {
+			e.buf = append(e.buf, cborSimple|21)
+		} else {
+			e.buf = append(e.buf, cborSimple|20)
+		}
+	case int:
+		e.encodeInt(int64(v))
+	case int8:
+		e.encodeInt(int64(v))
+	case int16:
+		e.encodeInt(int64(v))
+	case int32:
+		e.encodeInt(int64(v))
+	case int64:
+		e.encodeInt(v)
+	case uint:
+		e.head(cborUint, uint64(v))
+	case uint8:
+		e.head(cborUint, uint64(v))
+	case uint16:
+		e.head(cborUint, uint64(v))
+	case uint32:
+		e.head(cborUint, uint64(v))
+	case uint64:
+		e.head(cborUint, v)
+	case float32:
+		e.buf = appendUint32(append(e.buf, cborSimple|26), math.Float32bits(v))
+	case float64:
+		e.buf = appendUint64(append(e.buf, cborSimple|27), math.Float64bits(v))
+	case string:
+		e.head(cborText, uint64(len(v)))
+		e.buf = append(e.buf, v...)
+	case []byte:
+		e.head(cborBytes, uint64(len(v)))
+		e.buf = append(e.buf, v...)
+	case time.Time:
+		s := v.Format(time.RFC3339Nano)
+		e.head(cborTag, cborTagTimeString)
+		e.head(cborText, uint64(len(s)))
+		e.buf = append(e.buf, s...)
+	case Map:
+		return e.encodeMap(v)
+	case map[string]interface{}:
+		return e.encodeMap(v)
+	case []Map:
+		e.head(cborArray, uint64(len(v)))
+		for i := range v {
+			if err := e.encodeMap(v[i]); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		e.head(cborArray, uint64(len(v)))
+		for i := range v {
+			if err := e.encode(v[i]); err != nil {
+				return err
+			}
+		}
+	default:
+		value, err := reflectValue(reflect.ValueOf(value))
+		if err != nil {
+			return err
+		}
+		return e.encode(value)
+	}
+	return nil
+}
+
+type cborDecoder struct {
+	buf   []byte
+	pos   int
+	depth int
+}
+
+// enter increases the nesting depth of the arrays, maps and tags, call the returned function to leave
+func (d *cborDecoder) enter() (func(), error) {
+	if d.depth >= maxDecodeDepth {
+		return nil, ErrMaxDepth
+	}
+	d.depth++
+	return func() { d.depth-- }, nil
+}
+
+func (d *cborDecoder) next(n uint64) ([]byte, error) {
+	if n > uint64(len(d.buf)-d.pos) {
+		return nil, ErrShortData
+	}
+	b := d.buf[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return b, nil
+}
+
+// head reads the major type and the argument, indefinite is true if the length is indefinite
+func (d *cborDecoder) head() (major byte, info byte, n uint64, err error) {
+	b, err := d.next(1)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	major, info = b[0]&0xe0, b[0]&0x1f
+	switch {
+	case info < 24:
+		return major, info, uint64(info), nil
+	case info <= 27:
+		b, err := d.next(1 << (info - 24))
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		switch len(b) {
+		case 1:
+			n = uint64(b[0])
+		case 2:
+			n = uint64(binary.BigEndian.Uint16(b))
+		case 4:
+			n = uint64(binary.BigEndian.Uint32(b))
+		default:
+			n = binary.BigEndian.Uint64(b)
+		}
+		return major, info, n, nil
+	case info == cborIndefinite:
+		return major, info, 0, nil
+	default:
+	}
+	return 0, 0, 0, fmt.Errorf("%w: 0x%x", ErrUnsupportedType, b[0])
+}
+
+// isBreak reports whether the next byte is the break of indefinite length items
+func (d *cborDecoder) isBreak() (bool, error) {
+	if d.pos >= len(d.buf) {
+		return false, ErrShortData
+	}
+	if d.buf[d.pos] == cborBreak {
+		d.pos++
+		return true, nil
+	}
+	return false, nil
+}
+
+func (d *cborDecoder) decode() (interface{}, error) {
+	major, info, n, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+	indefinite := info == cborIndefinite
+	switch major {
+	case cborUint:
+		if n > math.MaxInt64 {
+			return n, nil
+		}
+		return int64(n), nil
+	case cborNegInt:
+		if n > math.MaxInt64 {
+			return nil, fmt.Errorf("%w: integer overflows int64", ErrUnsupportedType)
+		}
+		return -1 - int64(n), nil
+	case cborBytes, cborText:
+		b, err := d.decodeString(major, n, indefinite)
+		if err != nil {
+			return nil, err
+		}
+		if major == cborText {
+			return string(b), nil
+		}
+		return b, nil
+	case cborArray:
+		return d.decodeArray(n, indefinite)
+	case cborMap:
+		return d.decodeMap(n, indefinite)
+	case cborTag:
+		return d.decodeTag(n)
+	default:
+	}
+	switch info {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22, 23:
+		return nil, nil
+	case 25:
+		return halfToFloat32(uint16(n)), nil
+	case 26:
+		return math.Float32frombits(uint32(n)), nil
+	case 27:
+		return math.Float64frombits(n), nil
+	default:
+	}
+	return nil, fmt.Errorf("%w: simple value %d", ErrUnsupportedType, n)
+}
+
+// decodeString decodes the bytes and text strings, the chunks of indefinite length are joined
+func (d *cborDecoder) decodeString(major byte, n uint64, indefinite bool) ([]byte, error) {
+	if !indefinite {
+		b, err := d.next(n)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte{}, b...), nil
+	}
+	var buf []byte
+	for {
+		brk, err := d.isBreak()
+		if err != nil {
+			return nil, err
+		}
+		if brk {
+			return buf, nil
+		}
+		chunk, info, n, err := d.head()
+		if err != nil {
+			return nil, err
+		}
+		if chunk != major || info == cborIndefinite {
+			return nil, fmt.Errorf("%w: invalid chunk", ErrUnsupportedType)
+		}
+		b, err := d.next(n)
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, b...)
+	}
+}
+
+func (d *cborDecoder) decodeArray(n uint64, indefinite bool) (interface{}, error) {
+	leave, err := d.enter()
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+	if !indefinite && n > uint64(len(d.buf)-d.pos) {
+		return nil, ErrShortData
+	}
+	var arr []interface{}
+	for i := uint64(0); indefinite || i < n; i++ {
+		if indefinite {
+			brk, err := d.isBreak()
+			if err != nil {
+				return nil, err
+			}
+			if brk {
+				break
+			}
+		}
+		v, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		arr = append(arr, v)
+	}
+	if arr == nil {
+		arr = []interface{}{}
+	}
+	return toMapArray(arr), nil
+}
+
+func (d *cborDecoder) decodeMap(n uint64, indefinite bool) (interface{}, error) {
+	leave, err := d.enter()
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+	if !indefinite && n > uint64(len(d.buf)-d.pos) {
+		return nil, ErrShortData
+	}
+	m := make(Map)
+	for i := uint64(0); indefinite || i < n; i++ {
+		if indefinite {
+			brk, err := d.isBreak()
+			if err != nil {
+				return nil, err
+			}
+			if brk {
+				break
+			}
+		}
+		k, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		v, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		m[fmt.Sprint(k)] = v
+	}
+	return m, nil
+}
+
+// decodeTag decodes the times of tag 0 and 1, the content of other tags is returned
+func (d *cborDecoder) decodeTag(tag uint64) (interface{}, error) {
+	leave, err := d.enter()
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+	v, err := d.decode()
+	if err != nil {
+		return nil, err
+	}
+	switch tag {
+	case cborTagTimeString:
+		if s, ok := v.(string); ok {
+			return time.Parse(time.RFC3339Nano, s)
+		}
+	case cborTagTimeEpoch:
+		switch t := v.(type) {
+		case int64:
+			return time.Unix(t, 0), nil
+		case uint64:
+			return time.Unix(int64(t), 0), nil
+		case float32:
+			sec, frac := math.Modf(float64(t))
+			return time.Unix(int64(sec), int64(frac*1e9)), nil
+		case float64:
+			sec, frac := math.Modf(t)
+			return time.Unix(int64(sec), int64(frac*1e9)), nil
+		}
+	default:
+		return v, nil
+	}
+	return nil, fmt.Errorf("%w: invalid time of tag %d", ErrUnsupportedType, tag)
+}
+
+// halfToFloat32 converts the IEEE 754 half precision float
+func halfToFloat32(h uint16) float32 {
+	sign := uint32(h>>15) << 31
+	exp := uint32(h>>10) & 0x1f
+	frac := uint32(h) & 0x3ff
+	switch exp {
+	case 0:
+		f := float32(frac) / (1 << 24)
+		if sign != 0 {
+			return -f
+		}
+		return f
+	case 0x1f:
+		return math.Float32frombits(sign | 0x7f800000 | frac<<13)
+	default:
+	}
+	return math.Float32frombits(sign | (exp+112)<<23 | frac<<13)
+}
@@ -0,0 +1,123 @@
+package gomap
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// cborOf returns the bytes of the map {"a": v} with the encoded v
+func cborOf(v ...byte) []byte {
+	return append([]byte{0xa1, 0x61, 'a'}, v...)
+}
+
+// the values are the examples of RFC 8949 Appendix A
+func TestMap_ToCBOR(t *testing.T) {
+	tests := []struct {
+		name string
+		m    Map
+		want []byte
+	}{
+		{name: "0", m: Map{"a": 0}, want: cborOf(0x00)},
+		{name: "23", m: Map{"a": 23}, want: cborOf(0x17)},
+		{name: "24", m: Map{"a": 24}, want: cborOf(0x18, 0x18)},
+		{name: "1000", m: Map{"a": 1000}, want: cborOf(0x19, 0x03, 0xe8)},
+		{name: "1000000", m: Map{"a": 1000000}, want: cborOf(0x1a, 0x00, 0x0f, 0x42, 0x40)},
+		{name: "1000000000000", m: Map{"a": int64(1000000000000)},
+			want: cborOf(0x1b, 0x00, 0x00, 0x00, 0xe8, 0xd4, 0xa5, 0x10, 0x00)},
+		{name: "max uint64", m: Map{"a": uint64(math.MaxUint64)},
+			want: cborOf(0x1b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff)},
+		{name: "-1", m: Map{"a": -1}, want: cborOf(0x20)},
+		{name: "-1000", m: Map{"a": -1000}, want: cborOf(0x39, 0x03, 0xe7)},
+		{name: "float32", m: Map{"a": float32(100000)}, want: cborOf(0xfa, 0x47, 0xc3, 0x50, 0x00)},
+		{name: "float64", m: Map{"a": 1.1}, want: cborOf(0xfb, 0x3f, 0xf1, 0x99, 0x99, 0x99, 0x99, 0x99, 0x9a)},
+		{name: "false", m: Map{"a": false}, want: cborOf(0xf4)},
+		{name: "true", m: Map{"a": true}, want: cborOf(0xf5)},
+		{name: "null", m: Map{"a": nil}, want: cborOf(0xf6)},
+		{name: "text", m: Map{"a": "IETF"}, want: cborOf(0x64, 'I', 'E', 'T', 'F')},
+		{name: "bytes", m: Map{"a": []byte{1, 2, 3, 4}}, want: cborOf(0x44, 0x01, 0x02, 0x03, 0x04)},
+		{name: "array", m: Map{"a": []interface{}{1, 2, 3}}, want: cborOf(0x83, 0x01, 0x02, 0x03)},
+		{name: "map array", m: Map{"a": []Map{{"b": 1}}}, want: cborOf(0x81, 0xa1, 0x61, 'b', 0x01)},
+		{name: "sorted keys", m: Map{"b": 1, "a": 2}, want: []byte{0xa2, 0x61, 'a', 0x02, 0x61, 'b', 0x01}},
+		{name: "tag 0 time", m: Map{"a": time.Date(2013, 3, 21, 20, 4, 0, 0, time.UTC)},
+			want: cborOf(append([]byte{0xc0, 0x74}, "2013-03-21T20:04:00Z"...)...)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.ToCBOR()
+			if err != nil {
+				t.Fatalf("ToCBOR() error = %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("ToCBOR() = % x, want % x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMap_ParseCBOR(t *testing.T) {
+	tests := []struct {
+		name    string
+		b       []byte
+		want    Map
+		wantErr error
+	}{
+		{name: "uint", b: cborOf(0x19, 0x03, 0xe8), want: Map{"a": int64(1000)}},
+		{name: "uint64 overflows int64", b: cborOf(0x1b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff),
+			want: Map{"a": uint64(math.MaxUint64)}},
+		{name: "negative", b: cborOf(0x39, 0x03, 0xe7), want: Map{"a": int64(-1000)}},
+		{name: "half 1.0", b: cborOf(0xf9, 0x3c, 0x00), want: Map{"a": float32(1)}},
+		{name: "half 65504", b: cborOf(0xf9, 0x7b, 0xff), want: Map{"a": float32(65504)}},
+		{name: "half -4.0", b: cborOf(0xf9, 0xc4, 0x00), want: Map{"a": float32(-4)}},
+		{name: "half subnormal", b: cborOf(0xf9, 0x00, 0x01), want: Map{"a": float32(5.960464477539063e-8)}},
+		{name: "half infinity", b: cborOf(0xf9, 0x7c, 0x00), want: Map{"a": float32(math.Inf(1))}},
+		{name: "undefined", b: cborOf(0xf7), want: Map{"a": nil}},
+		{name: "indefinite array", b: cborOf(0x9f, 0x01, 0x02, 0xff), want: Map{"a": []interface{}{int64(1), int64(2)}}},
+		{name: "indefinite map", b: []byte{0xbf, 0x61, 'a', 0x01, 0xff}, want: Map{"a": int64(1)}},
+		{name: "indefinite text", b: cborOf(0x7f, 0x65, 's', 't', 'r', 'e', 'a', 0x64, 'm', 'i', 'n', 'g', 0xff),
+			want: Map{"a": "streaming"}},
+		{name: "indefinite bytes", b: cborOf(0x5f, 0x42, 0x01, 0x02, 0x43, 0x03, 0x04, 0x05, 0xff),
+			want: Map{"a": []byte{1, 2, 3, 4, 5}}},
+		{name: "tag 0 time", b: cborOf(append([]byte{0xc0, 0x74}, "2013-03-21T20:04:00Z"...)...),
+			want: Map{"a": time.Date(2013, 3, 21, 20, 4, 0, 0, time.UTC)}},
+		{name: "tag 1 integer", b: cborOf(0xc1, 0x1a, 0x51, 0x4b, 0x67, 0xb0), want: Map{"a": time.Unix(1363896240, 0)}},
+		{name: "tag 1 float", b: cborOf(0xc1, 0xfb, 0x41, 0xd4, 0x52, 0xd9, 0xec, 0x20, 0x00, 0x00),
+			want: Map{"a": time.Unix(1363896240, 500000000)}},
+		{name: "other tag", b: cborOf(0xd8, 0x20, 0x63, 'u', 'r', 'l'), want: Map{"a": "url"}},
+		{name: "empty", b: nil, wantErr: ErrShortData},
+		{name: "truncated map", b: []byte{0xa1, 0x61}, wantErr: ErrShortData},
+		{name: "truncated argument", b: cborOf(0x19, 0x03), wantErr: ErrShortData},
+		{name: "truncated text", b: cborOf(0x64, 'I'), wantErr: ErrShortData},
+		{name: "array longer than data", b: cborOf(0x9b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff), wantErr: ErrShortData},
+		{name: "missing break", b: cborOf(0x9f, 0x01), wantErr: ErrShortData},
+		{name: "not a map", b: []byte{0x01}, wantErr: ErrUnsupportedType},
+		{name: "reserved argument", b: cborOf(0x1c), wantErr: ErrUnsupportedType},
+		{name: "negative overflows int64", b: cborOf(0x3b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff),
+			wantErr: ErrUnsupportedType},
+		{name: "invalid chunk", b: cborOf(0x5f, 0x61, 'x', 0xff), wantErr: ErrUnsupportedType},
+		{name: "invalid time", b: cborOf(0xc0, 0x01), wantErr: ErrUnsupportedType},
+		{name: "deep arrays", b: cborOf(bytes.Repeat([]byte{0x81}, maxDecodeDepth+1)...), wantErr: ErrMaxDepth},
+		{name: "deep tags", b: cborOf(bytes.Repeat([]byte{0xc6}, maxDecodeDepth+1)...), wantErr: ErrMaxDepth},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New()
+			err := got.ParseCBOR(tt.b)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseCBOR() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCBOR() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCBOR() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package gomap
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CSVSetting defines the format of CSV
+type CSVSetting struct {
+	// Comma is the field delimiter, default is ','
+	Comma rune
+}
+
+// CSVOption ...
+type CSVOption func(op *CSVSetting)
+
+// CSVComma set the field delimiter
+func CSVComma(r rune) CSVOption {
+	return func(op *CSVSetting) {
+		op.Comma = r
+	}
+}
+
+func newCSVSetting(opts ...CSVOption) *CSVSetting {
+	setting := &CSVSetting{Comma: ','}
+	for i := range opts {
+		opts[i](setting)
+	}
+	return setting
+}
+
+// ToCSV transfer maps to CSV, the columns are the union of the dotted paths of all leaves in sorted order,
+// the header is written in the first row
+func ToCSV(maps []Map, opts ...CSVOption) ([]byte, error) {
+	setting := newCSVSetting(opts...)
+	rows := make([]map[string]string, len(maps))
+	columns := make(map[string]struct{})
+	for i := range maps {
+		rows[i] = make(map[string]string)
+		flattenValues("", maps[i], rows[i])
+		for k := range rows[i] {
+			columns[k] = struct{}{}
+		}
+	}
+	header := make([]string, 0, len(columns))
+	for k := range columns {
+		header = append(header, k)
+	}
+	sort.Strings(header)
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = setting.Comma
+	if err := w.Write(header); err != nil {
+		return nil, fmt.Errorf("map to csv error:%w", err)
+	}
+	record := make([]string, len(header))
+	for _, row := range rows {
+		for i, k := range header {
+			record[i] = row[k]
+		}
+		if err := w.Write(record); err != nil {
+			return nil, fmt.Errorf("map to csv error:%w", err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("map to csv error:%w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// ParseCSV parse CSV bytes to maps, the first row is the header of dotted paths,
+// every value is set by its path, so "address.city" is set to the nested map.
+// The values are kept as string and the empty values are skipped.
+// ErrPathConflict is returned if a header is the parent of another, such as "a" and "a.b".
+func ParseCSV(b []byte, opts ...CSVOption) ([]Map, error) {
+	setting := newCSVSetting(opts...)
+	r := csv.NewReader(bytes.NewReader(b))
+	r.Comma = setting.Comma
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("csv to map error:%w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	header := records[0]
+	if err := checkHeader(header); err != nil {
+		return nil, fmt.Errorf("csv to map error:%w", err)
+	}
+	maps := make([]Map, 0, len(records)-1)
+	for _, record := range records[1:] {
+		m := New()
+		for i, v := range record {
+			if v == "" || i >= len(header) || header[i] == "" {
+				continue
+			}
+			m.Set(header[i], v)
+		}
+		maps = append(maps, m)
+	}
+	return maps, nil
+}
+
+// checkHeader returns ErrPathConflict if a path of header is the parent of another
+func checkHeader(header []string) error {
+	paths := make(map[string]bool, len(header))
+	for _, h := range header {
+		if h != "" {
+			paths[h] = true
+		}
+	}
+	for _, h := range header {
+		for i := strings.IndexByte(h, '.'); i >= 0; {
+			if paths[h[:i]] {
+				return fmt.Errorf("%w: %s and %s", ErrPathConflict, h[:i], h)
+			}
+			j := strings.IndexByte(h[i+1:], '.')
+			if j < 0 {
+				break
+			}
+			i += j + 1
+		}
+	}
+	return nil
+}
+
+// ToTSV is the same as ToCSV with the tab delimiter
+func ToTSV(maps []Map) ([]byte, error) {
+	return ToCSV(maps, CSVComma('\t'))
+}
+
+// ParseTSV is the same as ParseCSV with the tab delimiter
+func ParseTSV(b []byte) ([]Map, error) {
+	return ParseCSV(b, CSVComma('\t'))
+}
+
+// flattenValues formats all leaves of m to out with their dotted paths
+func flattenValues(prefix string, m Map, out map[string]string) {
+	for k, v := range m {
+		if prefix != "" {
+			k = prefix + "." + k
+		}
+		if sub, b := toMap(v); b {
+			flattenValues(k, sub, out)
+			continue
+		}
+		out[k] = formatValue(v)
+	}
+}
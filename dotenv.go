@@ -0,0 +1,109 @@
+package gomap
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidKey ...
+var ErrInvalidKey = errors.New("invalid key")
+
+// DotEnvSetting defines how the .env keys are mapped to the map keys
+type DotEnvSetting struct {
+	// Split convert the key DB_HOST to db.host when parsing,
+	// and db.host to DB_HOST when writing
+	Split bool
+}
+
+// DotEnvOption ...
+type DotEnvOption func(op *DotEnvSetting)
+
+// DotEnvSplit enable or disable the key splitting
+func DotEnvSplit(b bool) DotEnvOption {
+	return func(op *DotEnvSetting) {
+		op.Split = b
+	}
+}
+
+func newDotEnvSetting(opts ...DotEnvOption) *DotEnvSetting {
+	setting := &DotEnvSetting{}
+	for i := range opts {
+		opts[i](setting)
+	}
+	return setting
+}
+
+//ParseDotEnv parse .env bytes to map, the values are kept as string,
+// with DotEnvSplit it returns ErrPathConflict for the keys like APP and APP_PORT
+func (m Map) ParseDotEnv(b []byte, opts ...DotEnvOption) error {
+	setting := newDotEnvSetting(opts...)
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		i := strings.IndexByte(line, '=')
+		if i <= 0 {
+			return fmt.Errorf("dotenv to map error:invalid line %d", n)
+		}
+		key := strings.TrimSpace(line[:i])
+		value := strings.TrimSpace(line[i+1:])
+		if value != "" && value[0] != '"' && value[0] != '\'' {
+			if j := strings.Index(value, " #"); j >= 0 {
+				value = strings.TrimSpace(value[:j])
+			}
+		}
+		value = unquoteValue(value)
+		if setting.Split {
+			if err := setPathStrict(m, strings.Split(strings.ToLower(key), "_"), value); err != nil {
+				return fmt.Errorf("dotenv to map error:%w at line %d", err, n)
+			}
+			continue
+		}
+		m[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("dotenv to map error:%w", err)
+	}
+	return nil
+}
+
+//ToDotEnv transfer map to .env, the keys of nested maps are joined with '_',
+// the keys are written in sorted order. With DotEnvSplit it returns ErrInvalidKey
+// for the keys containing '_', such as db.max_conn, they would be parsed back as db.max.conn
+func (m Map) ToDotEnv(opts ...DotEnvOption) ([]byte, error) {
+	setting := newDotEnvSetting(opts...)
+	var buf bytes.Buffer
+	if err := writeDotEnv(&buf, "", m, setting); err != nil {
+		return nil, fmt.Errorf("map to dotenv error:%w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func writeDotEnv(buf *bytes.Buffer, prefix string, m Map, setting *DotEnvSetting) error {
+	for _, k := range m.SortKeys() {
+		key := k
+		if setting.Split {
+			if strings.Contains(k, "_") {
+				return fmt.Errorf("%w: %q can not be split", ErrInvalidKey, k)
+			}
+			key = strings.ToUpper(strings.Replace(k, ".", "_", -1))
+		}
+		if prefix != "" {
+			key = prefix + "_" + key
+		}
+		if sub, b := toMap(m[k]); b {
+			if err := writeDotEnv(buf, key, sub, setting); err != nil {
+				return err
+			}
+			continue
+		}
+		buf.WriteString(key + "=" + quoteValue(formatValue(m[k])) + "\n")
+	}
+	return nil
+}
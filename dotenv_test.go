@@ -0,0 +1,90 @@
+package gomap
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMap_ToDotEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       Map
+		opts    []DotEnvOption
+		want    string
+		wantErr error
+	}{
+		{name: "flat", m: Map{"B": "2", "A": "1"}, want: "A=1\nB=2\n"},
+		{name: "nested", m: Map{"DB": Map{"HOST": "x"}}, want: "DB_HOST=x\n"},
+		{name: "split", m: Map{"db": Map{"host": "x", "port": 5432}}, opts: []DotEnvOption{DotEnvSplit(true)},
+			want: "DB_HOST=x\nDB_PORT=5432\n"},
+		{name: "split underscore", m: Map{"db": Map{"max_conn": 10}}, opts: []DotEnvOption{DotEnvSplit(true)},
+			wantErr: ErrInvalidKey},
+		{name: "underscore without split", m: Map{"MAX_CONN": "10"}, want: "MAX_CONN=10\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.ToDotEnv(tt.opts...)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ToDotEnv() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ToDotEnv() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("ToDotEnv() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMap_ParseDotEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		b       string
+		opts    []DotEnvOption
+		want    Map
+		wantErr error
+	}{
+		{name: "flat", b: "# c\nexport A=1\nB=\"x y\" \nC=z # c\n", want: Map{"A": "1", "B": "x y", "C": "z"}},
+		{name: "split", b: "DB_HOST=x\nDB_PORT=5432\n", opts: []DotEnvOption{DotEnvSplit(true)},
+			want: Map{"db": Map{"host": "x", "port": "5432"}}},
+		{name: "split conflict", b: "APP=1\nAPP_PORT=2\n", opts: []DotEnvOption{DotEnvSplit(true)}, wantErr: ErrPathConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New()
+			err := got.ParseDotEnv([]byte(tt.b), tt.opts...)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseDotEnv() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseDotEnv() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseDotEnv() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMap_DotEnvRoundTrip(t *testing.T) {
+	m := Map{"db": Map{"host": "x", "port": "5432"}, "debug": "true"}
+	b, err := m.ToDotEnv(DotEnvSplit(true))
+	if err != nil {
+		t.Fatalf("ToDotEnv() error = %v", err)
+	}
+	got := New()
+	if err := got.ParseDotEnv(b, DotEnvSplit(true)); err != nil {
+		t.Fatalf("ParseDotEnv() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, m) {
+		t.Errorf("ParseDotEnv(ToDotEnv()) = %v, want %v", got, m)
+	}
+}
@@ -0,0 +1,145 @@
+package gomap
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FlattenIndex is the format of the array indexes in the flattened keys
+type FlattenIndex int
+
+const (
+	// FlattenIndexDot write the indexes as the path elements: a.0.b
+	FlattenIndexDot FlattenIndex = iota
+	// FlattenIndexBracket write the indexes in brackets: a[0].b
+	FlattenIndexBracket
+	// FlattenIndexNone keep the arrays as the values
+	FlattenIndexNone
+)
+
+// FlattenSetting defines the keys of Flatten and Unflatten
+type FlattenSetting struct {
+	// Separator is put between the keys, default is "."
+	Separator string
+	Index     FlattenIndex
+}
+
+// FlattenOption ...
+type FlattenOption func(op *FlattenSetting)
+
+// FlattenSeparator set the separator between the keys, such as "__" for environment variables
+func FlattenSeparator(sep string) FlattenOption {
+	return func(op *FlattenSetting) {
+		op.Separator = sep
+	}
+}
+
+// FlattenIndexStyle set the format of the array indexes
+func FlattenIndexStyle(index FlattenIndex) FlattenOption {
+	return func(op *FlattenSetting) {
+		op.Index = index
+	}
+}
+
+// defaultFlattenSetting returns the setting used by Flatten and Unflatten when there is no option
+func defaultFlattenSetting() *FlattenSetting {
+	return &FlattenSetting{
+		Separator: ".",
+		Index:     FlattenIndexDot,
+	}
+}
+
+func newFlattenSetting(opts ...FlattenOption) *FlattenSetting {
+	setting := defaultFlattenSetting()
+	for i := range opts {
+		opts[i](setting)
+	}
+	if setting.Separator == "" {
+		setting.Separator = defaultFlattenSetting().Separator
+	}
+	return setting
+}
+
+//Flatten transfer map to a single level map keyed by the full paths,
+// the empty maps and arrays are kept as the values
+func (m Map) Flatten(opts ...FlattenOption) Map {
+	setting := newFlattenSetting(opts...)
+	flat := New()
+	// the root is not a value, so the empty map is flattened to the empty map
+	for k, v := range m {
+		setting.flatten(flat, k, v)
+	}
+	return flat
+}
+
+func (s *FlattenSetting) flatten(flat Map, prefix string, value interface{}) {
+	if sub, b := toMap(value); b && len(sub) > 0 {
+		for k, v := range sub {
+			s.flatten(flat, prefix+s.Separator+k, v)
+		}
+		return
+	}
+	if s.Index != FlattenIndexNone && prefix != "" {
+		if arr, b := walkArray(value); b && len(arr) > 0 {
+			for i := range arr {
+				s.flatten(flat, s.indexKey(prefix, i), arr[i])
+			}
+			return
+		}
+	}
+	flat[prefix] = value
+}
+
+// walkArray returns the arrays recursed by Walk
+func walkArray(value interface{}) ([]interface{}, bool) {
+	switch value.(type) {
+	case []interface{}, []Map:
+		return toArray(value)
+	default:
+	}
+	return nil, false
+}
+
+func (s *FlattenSetting) indexKey(prefix string, i int) string {
+	if s.Index == FlattenIndexBracket {
+		return prefix + "[" + strconv.Itoa(i) + "]"
+	}
+	return prefix + s.Separator + strconv.Itoa(i)
+}
+
+//Unflatten transfer the flattened map back to nested maps by SetPath,
+// the maps which keys are all indexes are transferred to arrays,
+// so a map like {"0": ...} can not be restored with FlattenIndexDot.
+// It returns ErrPathConflict if a key is both a value and the parent of other keys, such as "a" and "a.b"
+func Unflatten(flat Map, opts ...FlattenOption) (Map, error) {
+	setting := newFlattenSetting(opts...)
+	m := New()
+	for _, k := range flat.SortKeys() {
+		if err := setPathStrict(m, setting.splitKey(k), flat[k]); err != nil {
+			return nil, fmt.Errorf("unflatten map error:%w", err)
+		}
+	}
+	if setting.Index != FlattenIndexNone {
+		compactArrays(m)
+	}
+	return m, nil
+}
+
+func (s *FlattenSetting) splitKey(k string) []string {
+	keys := strings.Split(k, s.Separator)
+	if s.Index != FlattenIndexBracket {
+		return keys
+	}
+	var path []string
+	for _, key := range keys {
+		// a[0][1] is a, 0, 1
+		if i := strings.IndexByte(key, '['); i > 0 && strings.HasSuffix(key, "]") {
+			path = append(path, key[:i])
+			path = append(path, strings.Split(key[i+1:len(key)-1], "][")...)
+			continue
+		}
+		path = append(path, key)
+	}
+	return path
+}
@@ -0,0 +1,73 @@
+package gomap
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMap_Flatten(t *testing.T) {
+	tests := []struct {
+		name string
+		m    Map
+		opts []FlattenOption
+		want Map
+	}{
+		{name: "empty", m: Map{}, want: Map{}},
+		{name: "empty key", m: Map{"": 1}, want: Map{"": 1}},
+		{name: "nested", m: Map{"a": Map{"b": 1, "c": Map{}}, "d": []interface{}{}},
+			want: Map{"a.b": 1, "a.c": Map{}, "d": []interface{}{}}},
+		{name: "arrays", m: Map{"a": []interface{}{1, Map{"b": 2}}, "c": []Map{{"d": 3}}},
+			want: Map{"a.0": 1, "a.1.b": 2, "c.0.d": 3}},
+		{name: "brackets", m: Map{"a": []Map{{"b": 1}}}, opts: []FlattenOption{FlattenIndexStyle(FlattenIndexBracket)},
+			want: Map{"a[0].b": 1}},
+		{name: "no index", m: Map{"a": Map{"b": []interface{}{1}}}, opts: []FlattenOption{FlattenIndexStyle(FlattenIndexNone)},
+			want: Map{"a.b": []interface{}{1}}},
+		{name: "separator", m: Map{"a": Map{"b": 1}}, opts: []FlattenOption{FlattenSeparator("__")}, want: Map{"a__b": 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.m.Flatten(tt.opts...)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Flatten() = %v, want %v", got, tt.want)
+			}
+			back, err := Unflatten(got, tt.opts...)
+			if err != nil {
+				t.Fatalf("Unflatten() error = %v", err)
+			}
+			if !reflect.DeepEqual(back, tt.m) {
+				t.Errorf("Unflatten(Flatten()) = %v, want %v", back, tt.m)
+			}
+		})
+	}
+}
+
+func TestUnflatten(t *testing.T) {
+	tests := []struct {
+		name    string
+		flat    Map
+		want    Map
+		wantErr error
+	}{
+		{name: "indexes", flat: Map{"a.1": "y", "a.0": "x"}, want: Map{"a": []interface{}{"x", "y"}}},
+		{name: "value then map", flat: Map{"a": 1, "a.b": 2}, wantErr: ErrPathConflict},
+		{name: "deep conflict", flat: Map{"a.b": 1, "a.b.c": 2}, wantErr: ErrPathConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Unflatten(tt.flat)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Unflatten() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unflatten() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Unflatten() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package gomap
+
+import "strconv"
+
+// isLeaf returns true if value is not a map or an array, or it is an empty map or array
+func isLeaf(value interface{}) bool {
+	switch v := value.(type) {
+	case Map:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	case []Map:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	default:
+	}
+	return true
+}
+
+//MapValues returns a copy of map with the leaves rewritten by fn, the leaf is dropped if keep is false.
+// The leaves are the values which are not map or array and the empty maps and arrays,
+// it recurses through Map, map[string]interface{}, []Map and []interface{} like Walk
+func (m Map) MapValues(fn func(path []string, v interface{}) (value interface{}, keep bool)) Map {
+	v, _ := mapValues(nil, m, fn, false)
+	return v.(Map)
+}
+
+//Filter returns a copy of map with the leaves which pred returns true,
+// the maps and arrays which become empty are removed, use it to strip the nil values before ToXML:
+//  m.Filter(func(_ []string, v interface{}) bool { return v != nil })
+func (m Map) Filter(pred func(path []string, v interface{}) bool) Map {
+	v, _ := mapValues(nil, m, func(path []string, v interface{}) (interface{}, bool) {
+		return v, pred(path, v)
+	}, true)
+	if v == nil {
+		return New()
+	}
+	return v.(Map)
+}
+
+//Reduce call fn for every leaf in the order of Walk with the result of the previous call,
+// it returns the last result or init if there is no leaf
+func (m Map) Reduce(init interface{}, fn func(acc interface{}, path []string, v interface{}) interface{}) interface{} {
+	acc := init
+	_ = m.Walk(func(path []string, value interface{}) error {
+		if isLeaf(value) {
+			acc = fn(acc, path, value)
+		}
+		return nil
+	})
+	return acc
+}
+
+// mapValues rewrite the leaves of value, the maps and arrays which become empty are dropped if prune is true
+func mapValues(path []string, value interface{}, fn func([]string, interface{}) (interface{}, bool), prune bool) (interface{}, bool) {
+	if path != nil && isLeaf(value) {
+		return fn(path, value)
+	}
+	switch v := value.(type) {
+	case Map:
+		m := make(Map, len(v))
+		for _, k := range v.SortKeys() {
+			if sub, keep := mapValues(appendPath(path, k), v[k], fn, prune); keep {
+				m[k] = sub
+			}
+		}
+		return m, !prune || len(m) > 0
+	case map[string]interface{}:
+		return mapValues(path, Map(v), fn, prune)
+	case []Map:
+		arr := make([]Map, 0, len(v))
+		for i := range v {
+			if sub, keep := mapValues(appendPath(path, strconv.Itoa(i)), v[i], fn, prune); keep {
+				if sm, b := sub.(Map); b {
+					arr = append(arr, sm)
+				}
+			}
+		}
+		return arr, !prune || len(arr) > 0
+	case []interface{}:
+		arr := make([]interface{}, 0, len(v))
+		for i := range v {
+			if sub, keep := mapValues(appendPath(path, strconv.Itoa(i)), v[i], fn, prune); keep {
+				arr = append(arr, sub)
+			}
+		}
+		return arr, !prune || len(arr) > 0
+	default:
+	}
+	return value, true
+}
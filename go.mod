@@ -3,6 +3,8 @@ module github.com/goexts/maputils
 go 1.13
 
 require (
+	github.com/BurntSushi/toml v1.3.2
 	github.com/fatih/structs v1.1.0
 	github.com/mitchellh/mapstructure v1.1.2
+	gopkg.in/yaml.v3 v3.0.1
 )
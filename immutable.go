@@ -0,0 +1,329 @@
+package gomap
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ImmutableMap is a persistent map which is never changed after it is created,
+// Set and Delete return a new map which copies only the maps on the path
+// and shares the other subtrees with the old one, so it is safe for concurrent reads.
+// The values returned by Get must not be modified.
+type ImmutableMap struct {
+	m Map
+}
+
+// NewImmutableMap create an ImmutableMap with a deep copy of m
+func NewImmutableMap(m Map) *ImmutableMap {
+	if m == nil {
+		return &ImmutableMap{m: New()}
+	}
+	return &ImmutableMap{m: copyTree(m).(Map)}
+}
+
+// copyTree deep copies the maps and slices of value, the nested map[string]interface{} are converted to Map
+func copyTree(value interface{}) interface{} {
+	switch v := value.(type) {
+	case Map:
+		m := make(Map, len(v))
+		for k := range v {
+			m[k] = copyTree(v[k])
+		}
+		return m
+	case map[string]interface{}:
+		return copyTree(Map(v))
+	case []Map:
+		arr := make([]Map, len(v))
+		for i := range v {
+			arr[i] = copyTree(v[i]).(Map)
+		}
+		return arr
+	case []interface{}:
+		arr := make([]interface{}, len(v))
+		for i := range v {
+			arr[i] = copyTree(v[i])
+		}
+		return arr
+	default:
+	}
+	return value
+}
+
+// Set returns a new map with the value of the dotted key
+func (im *ImmutableMap) Set(key string, v interface{}) *ImmutableMap {
+	return im.SetPath(strings.Split(key, "."), v)
+}
+
+// SetPath returns a new map with the value of the path,
+// the intermediate maps are created like Map.SetPath
+func (im *ImmutableMap) SetPath(keys []string, v interface{}) *ImmutableMap {
+	if len(keys) == 0 {
+		return im
+	}
+	v = copyTree(v)
+	return &ImmutableMap{m: setPersistent(im.m, keys, v)}
+}
+
+func copyMap(m Map, size int) Map {
+	n := make(Map, len(m)+size)
+	for k := range m {
+		n[k] = m[k]
+	}
+	return n
+}
+
+func setPersistent(node Map, keys []string, v interface{}) Map {
+	n := copyMap(node, 1)
+	if len(keys) == 1 {
+		n[keys[0]] = v
+		return n
+	}
+	switch child := node[keys[0]].(type) {
+	case Map:
+		n[keys[0]] = setPersistent(child, keys[1:], v)
+	case []Map:
+		// go to most recent element
+		arr := append([]Map{}, child...)
+		if len(arr) == 0 {
+			arr = append(arr, nil)
+		}
+		arr[len(arr)-1] = setPersistent(arr[len(arr)-1], keys[1:], v)
+		n[keys[0]] = arr
+	default:
+		n[keys[0]] = setPersistent(nil, keys[1:], v)
+	}
+	return n
+}
+
+// Delete returns a new map without the dotted key, it returns itself if the key is not exist
+func (im *ImmutableMap) Delete(key string) *ImmutableMap {
+	if key == "" {
+		return im
+	}
+	return im.DeletePath(strings.Split(key, "."))
+}
+
+// DeletePath returns a new map without the path, it returns itself if the path is not exist
+func (im *ImmutableMap) DeletePath(keys []string) *ImmutableMap {
+	if len(keys) == 0 || !hasPersistent(im.m, keys) {
+		return im
+	}
+	return &ImmutableMap{m: deletePersistent(im.m, keys)}
+}
+
+// hasPersistent walks the path as deletePersistent does and checks the last key exists,
+// the key with nil value exists
+func hasPersistent(node Map, keys []string) bool {
+	for _, key := range keys[:len(keys)-1] {
+		switch child := node[key].(type) {
+		case Map:
+			node = child
+		case []Map:
+			if len(child) == 0 {
+				return false
+			}
+			node = child[len(child)-1]
+		default:
+			return false
+		}
+	}
+	_, ok := node[keys[len(keys)-1]]
+	return ok
+}
+
+func deletePersistent(node Map, keys []string) Map {
+	n := copyMap(node, 0)
+	if len(keys) == 1 {
+		delete(n, keys[0])
+		return n
+	}
+	switch child := node[keys[0]].(type) {
+	case Map:
+		n[keys[0]] = deletePersistent(child, keys[1:])
+	case []Map:
+		arr := append([]Map{}, child...)
+		arr[len(arr)-1] = deletePersistent(arr[len(arr)-1], keys[1:])
+		n[keys[0]] = arr
+	default:
+	}
+	return n
+}
+
+// Merge returns a new map with the values of maps, the newer value will replace the older value
+func (im *ImmutableMap) Merge(maps ...Map) *ImmutableMap {
+	n := im
+	for _, m := range maps {
+		for k, v := range m {
+			n = n.Set(k, v)
+		}
+	}
+	return n
+}
+
+// Get get interface from map without default
+func (im *ImmutableMap) Get(key string) interface{} {
+	return im.m.Get(key)
+}
+
+// GetD get interface from map with default
+func (im *ImmutableMap) GetD(key string, d interface{}) interface{} {
+	return im.m.GetD(key, d)
+}
+
+// GetPath returns the element in the tree indicated by keys
+func (im *ImmutableMap) GetPath(keys []string) interface{} {
+	return im.m.GetPath(keys)
+}
+
+// GetMap get the sub map of key, it shares the tree with im
+func (im *ImmutableMap) GetMap(key string) *ImmutableMap {
+	if v, b := im.m.Get(key).(Map); b {
+		return &ImmutableMap{m: v}
+	}
+	return nil
+}
+
+// GetString get string from map without default
+func (im *ImmutableMap) GetString(key string) string {
+	return im.m.GetString(key)
+}
+
+// GetStringD get string from map with default
+func (im *ImmutableMap) GetStringD(key string, d string) string {
+	return im.m.GetStringD(key, d)
+}
+
+// GetBool get bool from map without default
+func (im *ImmutableMap) GetBool(key string) bool {
+	return im.m.GetBool(key)
+}
+
+// GetBoolD get bool from map with default
+func (im *ImmutableMap) GetBoolD(key string, d bool) bool {
+	return im.m.GetBoolD(key, d)
+}
+
+// GetInt64 get int64 from map without default
+func (im *ImmutableMap) GetInt64(key string) (int64, bool) {
+	return im.m.GetInt64(key)
+}
+
+// GetInt64D get int64 from map with default
+func (im *ImmutableMap) GetInt64D(key string, d int64) int64 {
+	return im.m.GetInt64D(key, d)
+}
+
+// GetNumber get float64 from map without default
+func (im *ImmutableMap) GetNumber(key string) (float64, bool) {
+	return im.m.GetNumber(key)
+}
+
+// GetNumberD get float64 from map with default
+func (im *ImmutableMap) GetNumberD(key string, d float64) float64 {
+	return im.m.GetNumberD(key, d)
+}
+
+//Has check if key exist
+func (im *ImmutableMap) Has(key string) bool {
+	return im.m.Has(key)
+}
+
+// Len returns the number of the top level keys
+func (im *ImmutableMap) Len() int {
+	return len(im.m)
+}
+
+//Range range all top level keys
+func (im *ImmutableMap) Range(f func(key string, value interface{}) bool) {
+	im.m.Range(f)
+}
+
+// Snapshot returns im itself, it costs nothing because im is never changed,
+// the later Set and Delete return the new maps
+func (im *ImmutableMap) Snapshot() *ImmutableMap {
+	return im
+}
+
+// ToMap returns a deep copy of the map which can be modified
+func (im *ImmutableMap) ToMap() Map {
+	return copyTree(im.m).(Map)
+}
+
+//String transfer map to JSON string
+func (im *ImmutableMap) String() string {
+	return im.m.String()
+}
+
+// MarshalJSON implements json.Marshaler
+func (im *ImmutableMap) MarshalJSON() ([]byte, error) {
+	return json.Marshal(im.m)
+}
+
+// AtomicMap holds an ImmutableMap which can be replaced atomically,
+// Load never blocks and always returns a consistent snapshot, the writers are serialized
+type AtomicMap struct {
+	mu sync.Mutex
+	v  atomic.Value
+}
+
+// NewAtomicMap create an AtomicMap with a deep copy of m
+func NewAtomicMap(m Map) *AtomicMap {
+	a := &AtomicMap{}
+	a.v.Store(NewImmutableMap(m))
+	return a
+}
+
+// Load returns the current snapshot, it is not changed by the later writes
+func (a *AtomicMap) Load() *ImmutableMap {
+	if im, b := a.v.Load().(*ImmutableMap); b && im != nil {
+		return im
+	}
+	return NewImmutableMap(nil)
+}
+
+// Store replace the current snapshot with im, a nil im is stored as an empty map
+func (a *AtomicMap) Store(im *ImmutableMap) {
+	if im == nil {
+		im = NewImmutableMap(nil)
+	}
+	a.mu.Lock()
+	a.v.Store(im)
+	a.mu.Unlock()
+}
+
+// Update replace the current snapshot with the result of fn,
+// the readers see either the old or the new snapshot, never the changes in fn.
+// A nil result is stored as an empty map
+func (a *AtomicMap) Update(fn func(im *ImmutableMap) *ImmutableMap) *ImmutableMap {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	im := fn(a.Load())
+	if im == nil {
+		im = NewImmutableMap(nil)
+	}
+	a.v.Store(im)
+	return im
+}
+
+// Set set value to the dotted key of the current snapshot
+func (a *AtomicMap) Set(key string, v interface{}) *ImmutableMap {
+	return a.Update(func(im *ImmutableMap) *ImmutableMap {
+		return im.Set(key, v)
+	})
+}
+
+// Delete delete the dotted key of the current snapshot
+func (a *AtomicMap) Delete(key string) *ImmutableMap {
+	return a.Update(func(im *ImmutableMap) *ImmutableMap {
+		return im.Delete(key)
+	})
+}
+
+// Merge merge maps to the current snapshot as one change
+func (a *AtomicMap) Merge(maps ...Map) *ImmutableMap {
+	return a.Update(func(im *ImmutableMap) *ImmutableMap {
+		return im.Merge(maps...)
+	})
+}
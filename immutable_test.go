@@ -0,0 +1,116 @@
+package gomap
+
+import (
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestImmutableMap_DeletePath(t *testing.T) {
+	src := Map{"a": nil, "b": Map{"c": nil, "d": 1}, "l": []Map{{"x": 1}, {"y": nil}}}
+	tests := []struct {
+		name string
+		keys []string
+		want Map
+		same bool
+	}{
+		{name: "nil value", keys: []string{"a"}, want: Map{"b": Map{"c": nil, "d": 1}, "l": []Map{{"x": 1}, {"y": nil}}}},
+		{name: "nested nil value", keys: []string{"b", "c"}, want: Map{"a": nil, "b": Map{"d": 1}, "l": []Map{{"x": 1}, {"y": nil}}}},
+		{name: "last element of array", keys: []string{"l", "y"}, want: Map{"a": nil, "b": Map{"c": nil, "d": 1}, "l": []Map{{"x": 1}, {}}}},
+		{name: "missing", keys: []string{"z"}, same: true},
+		{name: "under a value", keys: []string{"a", "z"}, same: true},
+		{name: "not the last element", keys: []string{"l", "x"}, same: true},
+		{name: "empty", keys: nil, same: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			im := NewImmutableMap(src)
+			got := im.DeletePath(tt.keys)
+			if tt.same {
+				if got != im {
+					t.Errorf("DeletePath() = %v, want itself", got)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got.ToMap(), tt.want) {
+				t.Errorf("DeletePath() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(im.ToMap(), src) {
+				t.Errorf("DeletePath() changed the old map to %v", im)
+			}
+		})
+	}
+}
+
+func TestImmutableMap_Set(t *testing.T) {
+	v := Map{"c": 1}
+	im := NewImmutableMap(nil)
+	next := im.Set("a.b", v).Set("d", []interface{}{v})
+	v["c"] = 2
+	want := Map{"a": Map{"b": Map{"c": 1}}, "d": []interface{}{Map{"c": 1}}}
+	if !reflect.DeepEqual(next.ToMap(), want) {
+		t.Errorf("Set() = %v, want %v", next, want)
+	}
+	if im.Len() != 0 {
+		t.Errorf("Set() changed the old map to %v", im)
+	}
+}
+
+func TestAtomicMap_Nil(t *testing.T) {
+	a := NewAtomicMap(Map{"a": 1})
+	a.Store(nil)
+	if got := a.Load().Get("a"); got != nil {
+		t.Errorf("Load().Get() after Store(nil) = %v, want nil", got)
+	}
+	a.Set("a", 1)
+	a.Update(func(im *ImmutableMap) *ImmutableMap { return nil })
+	if got := a.Load().Len(); got != 0 {
+		t.Errorf("Load().Len() after Update() returns nil = %v, want 0", got)
+	}
+	var zero AtomicMap
+	if got := zero.Load().Get("a"); got != nil {
+		t.Errorf("zero AtomicMap Load().Get() = %v, want nil", got)
+	}
+}
+
+// run with -race, the readers use the snapshots while the writers replace them
+func TestAtomicMap_Concurrent(t *testing.T) {
+	a := NewAtomicMap(nil)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				a.Update(func(im *ImmutableMap) *ImmutableMap {
+					n, _ := im.GetInt64("count")
+					return im.Set("count", n+1)
+				})
+				v := Map{"j": j}
+				a.Set("m."+strconv.Itoa(i), v)
+				v["j"] = -1
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				snap := a.Load()
+				before := snap.String()
+				if snap.String() != before {
+					t.Errorf("snapshot is changed")
+				}
+				snap.ToMap()["count"] = -1
+			}
+		}()
+	}
+	wg.Wait()
+	if got, _ := a.Load().GetInt64("count"); got != 8*200 {
+		t.Errorf("count = %v, want %v", got, 8*200)
+	}
+	for i := 0; i < 8; i++ {
+		if got := a.Load().Get("m." + strconv.Itoa(i) + ".j"); got != 199 {
+			t.Errorf("m.%d.j = %v, want 199", i, got)
+		}
+	}
+}
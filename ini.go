@@ -0,0 +1,188 @@
+package gomap
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrPathConflict is returned when a key is both a value and the parent of other keys, such as "a" and "a.b"
+var ErrPathConflict = errors.New("path conflict")
+
+// setPathStrict set v to the path of keys like SetPath, but it returns ErrPathConflict
+// instead of overwriting a value with a map or a map with a value
+func setPathStrict(m Map, keys []string, v interface{}) error {
+	subtree := m
+	for i, k := range keys[:len(keys)-1] {
+		next, exists := subtree[k]
+		if !exists {
+			sub := New()
+			subtree[k] = sub
+			subtree = sub
+			continue
+		}
+		sub, b := toMap(next)
+		if !b {
+			return fmt.Errorf("%w: %s", ErrPathConflict, strings.Join(keys[:i+1], "."))
+		}
+		subtree = sub
+	}
+	k := keys[len(keys)-1]
+	if old, exists := subtree[k]; exists {
+		_, oldMap := toMap(old)
+		_, newMap := toMap(v)
+		if oldMap != newMap {
+			return fmt.Errorf("%w: %s", ErrPathConflict, strings.Join(keys, "."))
+		}
+		if oldMap {
+			// keep the keys of the existing section
+			return nil
+		}
+	}
+	subtree[k] = v
+	return nil
+}
+
+//ParseINI parse INI bytes to map, the keys of a section are set to the nested map of the section,
+// so they can be got with "section.key", the keys before the first section are set to the top level.
+// The values are kept as string, ErrPathConflict is returned for a key which is also a section, such as "a" and [a]
+func (m Map) ParseINI(b []byte) error {
+	var section []string
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+		if line[0] == '[' {
+			if line[len(line)-1] != ']' {
+				return fmt.Errorf("ini to map error:invalid section at line %d", n)
+			}
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if name == "" {
+				return fmt.Errorf("ini to map error:empty section at line %d", n)
+			}
+			section = strings.Split(name, ".")
+			if err := setPathStrict(m, section, New()); err != nil {
+				return fmt.Errorf("ini to map error:%w at line %d", err, n)
+			}
+			continue
+		}
+		i := strings.IndexAny(line, "=:")
+		if i <= 0 {
+			return fmt.Errorf("ini to map error:invalid line %d", n)
+		}
+		key := strings.TrimSpace(line[:i])
+		path := append(append([]string{}, section...), key)
+		if err := setPathStrict(m, path, unquoteValue(strings.TrimSpace(line[i+1:]))); err != nil {
+			return fmt.Errorf("ini to map error:%w at line %d", err, n)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("ini to map error:%w", err)
+	}
+	return nil
+}
+
+//ToINI transfer map to INI, the nested maps are written as sections named by their path,
+// the keys are written in sorted order
+func (m Map) ToINI() ([]byte, error) {
+	var buf bytes.Buffer
+	writeINISection(&buf, "", m)
+	return buf.Bytes(), nil
+}
+
+func writeINISection(buf *bytes.Buffer, name string, m Map) {
+	keys := m.SortKeys()
+	if len(keys) == 0 && name != "" {
+		if buf.Len() > 0 {
+			buf.WriteByte('\n')
+		}
+		buf.WriteString("[" + name + "]\n")
+	}
+	var sections []string
+	hasValue := false
+	for _, k := range keys {
+		if _, b := toMap(m[k]); b {
+			sections = append(sections, k)
+			continue
+		}
+		if !hasValue && name != "" {
+			if buf.Len() > 0 {
+				buf.WriteByte('\n')
+			}
+			buf.WriteString("[" + name + "]\n")
+		}
+		hasValue = true
+		buf.WriteString(k + " = " + quoteValue(formatValue(m[k])) + "\n")
+	}
+	for _, k := range sections {
+		sub, _ := toMap(m[k])
+		if name != "" {
+			k = name + "." + k
+		}
+		writeINISection(buf, k, sub)
+	}
+}
+
+// toMap returns the value as Map if it is a Map or map[string]interface{}
+func toMap(v interface{}) (Map, bool) {
+	switch v0 := v.(type) {
+	case Map:
+		return v0, true
+	case map[string]interface{}:
+		return v0, true
+	default:
+	}
+	return nil, false
+}
+
+// formatValue formats the scalar value and the slice of values as text
+func formatValue(v interface{}) string {
+	switch v0 := v.(type) {
+	case string:
+		return v0
+	case []string:
+		return strings.Join(v0, ",")
+	case []interface{}:
+		values := make([]string, len(v0))
+		for i := range v0 {
+			values[i] = formatValue(v0[i])
+		}
+		return strings.Join(values, ",")
+	case nil:
+		return ""
+	default:
+	}
+	return fmt.Sprint(v)
+}
+
+// quoteValue quotes the value if it contains spaces or characters which need to be escaped
+func quoteValue(v string) string {
+	if strings.ContainsAny(v, " \"'#;\\\n\r\t") {
+		return strconv.Quote(v)
+	}
+	return v
+}
+
+// unquoteValue removes the quotes of value, the double quoted value is unescaped
+func unquoteValue(v string) string {
+	if len(v) < 2 {
+		return v
+	}
+	switch v[0] {
+	case '"':
+		if s, err := strconv.Unquote(v); err == nil {
+			return s
+		}
+	case '\'':
+		if v[len(v)-1] == '\'' {
+			return v[1 : len(v)-1]
+		}
+	default:
+	}
+	return v
+}
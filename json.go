@@ -0,0 +1,161 @@
+package gomap
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// JSONSetting defines how the map is encoded and decoded with JSON,
+// the keys are always written in sorted order
+type JSONSetting struct {
+	// Prefix and Indent are used to indent the output, no indentation if both are empty
+	Prefix string
+	Indent string
+	// EscapeHTML escape the characters <, > and & in strings
+	EscapeHTML bool
+	// UseNumber decode the numbers to json.Number instead of float64
+	UseNumber bool
+	// Int64 decode the integral numbers to int64 and others to float64,
+	// it has priority over UseNumber
+	Int64 bool
+}
+
+// JSONOption ...
+type JSONOption func(op *JSONSetting)
+
+func defaultJSONSetting() *JSONSetting {
+	return &JSONSetting{
+		EscapeHTML: true,
+	}
+}
+
+// JSONIndent indent the output with prefix and indent
+func JSONIndent(prefix, indent string) JSONOption {
+	return func(op *JSONSetting) {
+		op.Prefix = prefix
+		op.Indent = indent
+	}
+}
+
+// JSONEscapeHTML enable or disable the HTML escaping
+func JSONEscapeHTML(b bool) JSONOption {
+	return func(op *JSONSetting) {
+		op.EscapeHTML = b
+	}
+}
+
+// JSONUseNumber decode the numbers to json.Number
+func JSONUseNumber(b bool) JSONOption {
+	return func(op *JSONSetting) {
+		op.UseNumber = b
+	}
+}
+
+// JSONInt64 decode the integral numbers to int64
+func JSONInt64(b bool) JSONOption {
+	return func(op *JSONSetting) {
+		op.Int64 = b
+	}
+}
+
+func newJSONSetting(opts ...JSONOption) *JSONSetting {
+	setting := defaultJSONSetting()
+	for i := range opts {
+		opts[i](setting)
+	}
+	return setting
+}
+
+func (s *JSONSetting) encoder(w io.Writer) *json.Encoder {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(s.EscapeHTML)
+	if s.Prefix != "" || s.Indent != "" {
+		enc.SetIndent(s.Prefix, s.Indent)
+	}
+	return enc
+}
+
+func (s *JSONSetting) decoder(r io.Reader) *json.Decoder {
+	dec := json.NewDecoder(r)
+	if s.UseNumber || s.Int64 {
+		dec.UseNumber()
+	}
+	return dec
+}
+
+func (s *JSONSetting) decode(dec *json.Decoder, m *Map) error {
+	if err := dec.Decode(m); err != nil {
+		return err
+	}
+	if s.Int64 {
+		convertNumbers(*m)
+	}
+	return nil
+}
+
+// convertNumbers replace the json.Number values with int64 or float64
+func convertNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+	case Map:
+		for k := range v {
+			v[k] = convertNumbers(v[k])
+		}
+	case map[string]interface{}:
+		for k := range v {
+			v[k] = convertNumbers(v[k])
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = convertNumbers(v[i])
+		}
+	default:
+	}
+	return value
+}
+
+// EncodeJSON write the map to w as JSON
+func (m Map) EncodeJSON(w io.Writer, opts ...JSONOption) error {
+	return newJSONSetting(opts...).encoder(w).Encode(m)
+}
+
+// DecodeJSON read a JSON object from r to map
+func (m Map) DecodeJSON(r io.Reader, opts ...JSONOption) error {
+	setting := newJSONSetting(opts...)
+	return setting.decode(setting.decoder(r), &m)
+}
+
+// DecodeJSONArray read a JSON array from r and call fn with a Map for every element,
+// the elements are decoded one by one, so it can process very large arrays.
+// If fn returns an error, decoding stops and the error is returned.
+func DecodeJSONArray(r io.Reader, fn func(Map) error, opts ...JSONOption) error {
+	setting := newJSONSetting(opts...)
+	dec := setting.decoder(r)
+	t, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("json stream error:%w", err)
+	}
+	if d, b := t.(json.Delim); !b || d != '[' {
+		return fmt.Errorf("json stream error:expected array but got %v", t)
+	}
+	for dec.More() {
+		m := New()
+		if err := setting.decode(dec, &m); err != nil {
+			return fmt.Errorf("json stream error:%w", err)
+		}
+		if err := fn(m); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("json stream error:%w", err)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package gomap
 
 import (
+	"bytes"
 	"encoding/json"
 	"encoding/xml"
 	"errors"
@@ -503,12 +504,22 @@ func (m Map) SortKeys() []string {
 
 //ToXML transfer map to XML
 func (m Map) ToXML() ([]byte, error) {
-	return mapToXML(m, true)
+	return m.ToXMLWith()
 }
 
-//ParseXML parse XML bytes to map
+//ToXMLWith transfer map to XML with the options
+func (m Map) ToXMLWith(opts ...XMLOption) ([]byte, error) {
+	return mapToXML(m, true, newXMLSetting(opts...))
+}
+
+//ParseXML parse XML bytes to map, the text values are converted by the default setting
 func (m Map) ParseXML(b []byte) error {
-	return xmlToMap(m, b, true)
+	return m.ParseXMLWith(b)
+}
+
+//ParseXMLWith parse XML bytes to map, the text values are converted by the options
+func (m Map) ParseXMLWith(b []byte, opts ...XMLOption) error {
+	return xmlToMap(m, b, true, newXMLSetting(opts...))
 }
 
 //ToJSON transfer map to JSON
@@ -522,6 +533,15 @@ func (m Map) ParseJSON(b []byte) error {
 	return json.Unmarshal(b, &m)
 }
 
+//ParseJSONWith parse JSON bytes to map, the numbers are decoded by the options
+func (m Map) ParseJSONWith(b []byte, opts ...JSONOption) error {
+	setting := newJSONSetting(opts...)
+	if !setting.UseNumber && !setting.Int64 {
+		return json.Unmarshal(b, &m)
+	}
+	return setting.decode(setting.decoder(bytes.NewReader(b)), &m)
+}
+
 // Append append source map to target map;
 // if the key value is exist and it is a []interface value, this will append into it
 // otherwise, it will replace the value
@@ -569,7 +589,7 @@ func (m Map) Only(keys []string) Map {
 	return p
 }
 
-//Expect get map expect keys
+//Expect get map expect keys, it only deletes the exact keys, see Except for the patterns
 func (m Map) Expect(keys []string) Map {
 	p := m.Clone()
 	size := len(keys)
@@ -671,16 +691,18 @@ func (m Map) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 	if len(m) == 0 {
 		return ErrNilMap
 	}
+	setting := newXMLSetting()
 	if start.Name.Local == "root" {
-		return marshalXML(m, e, xml.StartElement{Name: xml.Name{Local: "root"}})
+		return marshalXML(m, e, xml.StartElement{Name: xml.Name{Local: "root"}}, "", setting)
 	}
-	return marshalXML(m, e, xml.StartElement{Name: xml.Name{Local: "xml"}})
+	return marshalXML(m, e, xml.StartElement{Name: xml.Name{Local: "xml"}}, "", setting)
 }
 
-// UnmarshalXML ...
+// UnmarshalXML implements xml.Unmarshaler, the text values are kept as string
 func (m Map) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	setting := newXMLSetting(XMLCast(false))
 	if start.Name.Local == "root" {
-		return unmarshalXML(m, d, xml.StartElement{Name: xml.Name{Local: "root"}}, false)
+		return unmarshalXML(m, d, xml.StartElement{Name: xml.Name{Local: "root"}}, setting)
 	}
-	return unmarshalXML(m, d, xml.StartElement{Name: xml.Name{Local: "xml"}}, false)
+	return unmarshalXML(m, d, xml.StartElement{Name: xml.Name{Local: "xml"}}, setting)
 }
@@ -0,0 +1,538 @@
+package gomap
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+)
+
+// ErrUnsupportedType ...
+var ErrUnsupportedType = errors.New("error unsupported type")
+
+// ErrShortData ...
+var ErrShortData = errors.New("unexpected end of data")
+
+// ErrMaxDepth ...
+var ErrMaxDepth = errors.New("exceeded max nesting depth")
+
+// maxDecodeDepth limits the nesting of the maps and arrays decoded from the binary formats
+const maxDecodeDepth = 10000
+
+// msgpackTimeExt is the timestamp extension type -1 of MessagePack
+const msgpackTimeExt byte = 0xff
+
+//ToMsgPack transfer map to MessagePack, time.Time is written with the timestamp extension type
+func (m Map) ToMsgPack() ([]byte, error) {
+	e := &msgpackEncoder{}
+	if err := e.encode(m); err != nil {
+		return nil, fmt.Errorf("map to msgpack error:%w", err)
+	}
+	return e.buf, nil
+}
+
+//ParseMsgPack parse MessagePack bytes to map, the integers are decoded to int64
+// (uint64 if it overflows int64), the arrays of maps to []Map and the timestamps to time.Time
+func (m Map) ParseMsgPack(b []byte) error {
+	d := &msgpackDecoder{buf: b}
+	v, err := d.decode()
+	if err != nil {
+		return fmt.Errorf("msgpack to map error:%w", err)
+	}
+	mp, ok := v.(Map)
+	if !ok {
+		return fmt.Errorf("msgpack to map error:%w", ErrUnsupportedType)
+	}
+	for k := range mp {
+		m[k] = mp[k]
+	}
+	return nil
+}
+
+type msgpackEncoder struct {
+	buf []byte
+}
+
+func (e *msgpackEncoder) writeByte(c byte) {
+	e.buf = append(e.buf, c)
+}
+
+func (e *msgpackEncoder) write16(c byte, v uint16) {
+	e.buf = append(e.buf, c, byte(v>>8), byte(v))
+}
+
+func (e *msgpackEncoder) write32(c byte, v uint32) {
+	e.buf = append(e.buf, c)
+	e.buf = appendUint32(e.buf, v)
+}
+
+func (e *msgpackEncoder) write64(c byte, v uint64) {
+	e.buf = append(e.buf, c)
+	e.buf = appendUint64(e.buf, v)
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	return appendUint32(appendUint32(b, uint32(v>>32)), uint32(v))
+}
+
+func (e *msgpackEncoder) encodeInt(v int64) {
+	switch {
+	case v >= 0:
+		e.encodeUint(uint64(v))
+	case v >= -32:
+		e.writeByte(byte(v))
+	case v >= math.MinInt8:
+		e.buf = append(e.buf, 0xd0, byte(v))
+	case v >= math.MinInt16:
+		e.write16(0xd1, uint16(v))
+	case v >= math.MinInt32:
+		e.write32(0xd2, uint32(v))
+	default:
+		e.write64(0xd3, uint64(v))
+	}
+}
+
+func (e *msgpackEncoder) encodeUint(v uint64) {
+	switch {
+	case v <= 0x7f:
+		e.writeByte(byte(v))
+	case v <= math.MaxUint8:
+		e.buf = append(e.buf, 0xcc, byte(v))
+	case v <= math.MaxUint16:
+		e.write16(0xcd, uint16(v))
+	case v <= math.MaxUint32:
+		e.write32(0xce, uint32(v))
+	default:
+		e.write64(0xcf, v)
+	}
+}
+
+func (e *msgpackEncoder) encodeString(v string) {
+	n := len(v)
+	switch {
+	case n <= 31:
+		e.writeByte(0xa0 | byte(n))
+	case n <= math.MaxUint8:
+		e.buf = append(e.buf, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		e.write16(0xda, uint16(n))
+	default:
+		e.write32(0xdb, uint32(n))
+	}
+	e.buf = append(e.buf, v...)
+}
+
+func (e *msgpackEncoder) encodeBytes(v []byte) {
+	n := len(v)
+	switch {
+	case n <= math.MaxUint8:
+		e.buf = append(e.buf, 0xc4, byte(n))
+	case n <= math.MaxUint16:
+		e.write16(0xc5, uint16(n))
+	default:
+		e.write32(0xc6, uint32(n))
+	}
+	e.buf = append(e.buf, v...)
+}
+
+func (e *msgpackEncoder) encodeArrayLen(n int) {
+	switch {
+	case n <= 15:
+		e.writeByte(0x90 | byte(n))
+	case n <= math.MaxUint16:
+		e.write16(0xdc, uint16(n))
+	default:
+		e.write32(0xdd, uint32(n))
+	}
+}
+
+func (e *msgpackEncoder) encodeMapLen(n int) {
+	switch {
+	case n <= 15:
+		e.writeByte(0x80 | byte(n))
+	case n <= math.MaxUint16:
+		e.write16(0xde, uint16(n))
+	default:
+		e.write32(0xdf, uint32(n))
+	}
+}
+
+func (e *msgpackEncoder) encodeTime(t time.Time) {
+	sec, nsec := t.Unix(), uint64(t.Nanosecond())
+	switch {
+	case sec>>34 == 0 && nsec == 0:
+		e.buf = append(e.buf, 0xd6, msgpackTimeExt)
+		e.buf = appendUint32(e.buf, uint32(sec))
+	case sec>>34 == 0:
+		e.buf = append(e.buf, 0xd7, msgpackTimeExt)
+		e.buf = appendUint64(e.buf, nsec<<34|uint64(sec))
+	default:
+		e.buf = append(e.buf, 0xc7, 12, msgpackTimeExt)
+		e.buf = appendUint32(e.buf, uint32(nsec))
+		e.buf = appendUint64(e.buf, uint64(sec))
+	}
+}
+
+func (e *msgpackEncoder) encodeMap(m map[string]interface{}) error {
+	e.encodeMapLen(len(m))
+	for _, k := range Map(m).SortKeys() {
+		e.encodeString(k)
+		if err := e.encode(m[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *msgpackEncoder) encode(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		e.writeByte(0xc0)
+	case bool:
+		if v {
+			e.writeByte(0xc3)
+		} else {
+			e.writeByte(0xc2)
+		}
+	case int:
+		e.encodeInt(int64(v))
+	case int8:
+		e.encodeInt(int64(v))
+	case int16:
+		e.encodeInt(int64(v))
+	case int32:
+		e.encodeInt(int64(v))
+	case int64:
+		e.encodeInt(v)
+	case uint:
+		e.encodeUint(uint64(v))
+	case uint8:
+		e.encodeUint(uint64(v))
+	case uint16:
+		e.encodeUint(uint64(v))
+	case uint32:
+		e.encodeUint(uint64(v))
+	case uint64:
+		e.encodeUint(v)
+	case float32:
+		e.write32(0xca, math.Float32bits(v))
+	case float64:
+		e.write64(0xcb, math.Float64bits(v))
+	case string:
+		e.encodeString(v)
+	case []byte:
+		e.encodeBytes(v)
+	case time.Time:
+		e.encodeTime(v)
+	case Map:
+		return e.encodeMap(v)
+	case map[string]interface{}:
+		return e.encodeMap(v)
+	case []Map:
+		e.encodeArrayLen(len(v))
+		for i := range v {
+			if err := e.encodeMap(v[i]); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		e.encodeArrayLen(len(v))
+		for i := range v {
+			if err := e.encode(v[i]); err != nil {
+				return err
+			}
+		}
+	default:
+		return e.encodeReflect(reflect.ValueOf(value))
+	}
+	return nil
+}
+
+func (e *msgpackEncoder) encodeReflect(v reflect.Value) error {
+	value, err := reflectValue(v)
+	if err != nil {
+		return err
+	}
+	return e.encode(value)
+}
+
+// reflectValue converts the other slices to []interface{}, the maps with string keys
+// to map[string]interface{} and the named basic types to their underlying types,
+// so they can be written by the binary encoders
+func reflectValue(v reflect.Value) (interface{}, error) {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		arr := make([]interface{}, v.Len())
+		for i := range arr {
+			arr[i] = v.Index(i).Interface()
+		}
+		return arr, nil
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			break
+		}
+		m := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			m[iter.Key().String()] = iter.Value().Interface()
+		}
+		return m, nil
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return v.Elem().Interface(), nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	default:
+	}
+	return nil, fmt.Errorf("%w: %v", ErrUnsupportedType, v.Type())
+}
+
+type msgpackDecoder struct {
+	buf   []byte
+	pos   int
+	depth int
+}
+
+func (d *msgpackDecoder) next(n int) ([]byte, error) {
+	if n < 0 || len(d.buf)-d.pos < n {
+		return nil, ErrShortData
+	}
+	b := d.buf[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+func (d *msgpackDecoder) uint(n int) (uint64, error) {
+	b, err := d.next(n)
+	if err != nil {
+		return 0, err
+	}
+	switch n {
+	case 1:
+		return uint64(b[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	}
+	return binary.BigEndian.Uint64(b), nil
+}
+
+func (d *msgpackDecoder) length(n int) (int, error) {
+	l, err := d.uint(n)
+	if err != nil {
+		return 0, err
+	}
+	if l > uint64(len(d.buf)-d.pos) {
+		return 0, ErrShortData
+	}
+	return int(l), nil
+}
+
+func (d *msgpackDecoder) decode() (interface{}, error) {
+	b, err := d.next(1)
+	if err != nil {
+		return nil, err
+	}
+	c := b[0]
+	switch {
+	case c <= 0x7f:
+		return int64(c), nil
+	case c >= 0xe0:
+		return int64(int8(c)), nil
+	case c&0xf0 == 0x80:
+		return d.decodeMap(int(c & 0x0f))
+	case c&0xf0 == 0x90:
+		return d.decodeArray(int(c & 0x0f))
+	case c&0xe0 == 0xa0:
+		return d.decodeString(int(c & 0x1f))
+	}
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.length(1 << (c - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		v, err := d.next(n)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(nil), v...), nil
+	case 0xc7, 0xc8, 0xc9:
+		n, err := d.length(1 << (c - 0xc7))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeExt(n)
+	case 0xca:
+		v, err := d.uint(4)
+		return math.Float32frombits(uint32(v)), err
+	case 0xcb:
+		v, err := d.uint(8)
+		return math.Float64frombits(v), err
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		v, err := d.uint(1 << (c - 0xcc))
+		if err != nil {
+			return nil, err
+		}
+		if v > math.MaxInt64 {
+			return v, nil
+		}
+		return int64(v), nil
+	case 0xd0:
+		v, err := d.uint(1)
+		return int64(int8(v)), err
+	case 0xd1:
+		v, err := d.uint(2)
+		return int64(int16(v)), err
+	case 0xd2:
+		v, err := d.uint(4)
+		return int64(int32(v)), err
+	case 0xd3:
+		v, err := d.uint(8)
+		return int64(v), err
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return d.decodeExt(1 << (c - 0xd4))
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.length(1 << (c - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeString(n)
+	case 0xdc, 0xdd:
+		n, err := d.length(2 << (c - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeArray(n)
+	case 0xde, 0xdf:
+		n, err := d.length(2 << (c - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeMap(n)
+	default:
+	}
+	return nil, fmt.Errorf("%w: 0x%x", ErrUnsupportedType, c)
+}
+
+// enter increases the nesting depth of the arrays and maps, call the returned function to leave
+func (d *msgpackDecoder) enter() (func(), error) {
+	if d.depth >= maxDecodeDepth {
+		return nil, ErrMaxDepth
+	}
+	d.depth++
+	return func() { d.depth-- }, nil
+}
+
+func (d *msgpackDecoder) decodeString(n int) (interface{}, error) {
+	b, err := d.next(n)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (d *msgpackDecoder) decodeArray(n int) (interface{}, error) {
+	leave, err := d.enter()
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+	arr := make([]interface{}, n)
+	for i := 0; i < n; i++ {
+		v, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		arr[i] = v
+	}
+	return toMapArray(arr), nil
+}
+
+func (d *msgpackDecoder) decodeMap(n int) (interface{}, error) {
+	leave, err := d.enter()
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+	m := make(Map, n)
+	for i := 0; i < n; i++ {
+		k, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		v, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		m[fmt.Sprint(k)] = v
+	}
+	return m, nil
+}
+
+// decodeExt decodes the timestamp extension, the other extensions are returned as bytes
+func (d *msgpackDecoder) decodeExt(n int) (interface{}, error) {
+	t, err := d.next(1)
+	if err != nil {
+		return nil, err
+	}
+	b, err := d.next(n)
+	if err != nil {
+		return nil, err
+	}
+	if t[0] != msgpackTimeExt {
+		return append([]byte(nil), b...), nil
+	}
+	switch n {
+	case 4:
+		return time.Unix(int64(binary.BigEndian.Uint32(b)), 0), nil
+	case 8:
+		v := binary.BigEndian.Uint64(b)
+		return time.Unix(int64(v&(1<<34-1)), int64(v>>34)), nil
+	case 12:
+		nsec := binary.BigEndian.Uint32(b)
+		sec := binary.BigEndian.Uint64(b[4:])
+		return time.Unix(int64(sec), int64(nsec)), nil
+	default:
+	}
+	return nil, fmt.Errorf("%w: timestamp of %d bytes", ErrUnsupportedType, n)
+}
+
+// toMapArray returns []Map if all the values are Map
+func toMapArray(arr []interface{}) interface{} {
+	if len(arr) == 0 {
+		return arr
+	}
+	maps := make([]Map, len(arr))
+	for i := range arr {
+		m, ok := arr[i].(Map)
+		if !ok {
+			return arr
+		}
+		maps[i] = m
+	}
+	return maps
+}
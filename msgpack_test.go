@@ -0,0 +1,116 @@
+package gomap
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// msgpackOf returns the bytes of the map {"a": v} with the encoded v
+func msgpackOf(v ...byte) []byte {
+	return append([]byte{0x81, 0xa1, 'a'}, v...)
+}
+
+func TestMap_ToMsgPack(t *testing.T) {
+	tests := []struct {
+		name string
+		m    Map
+		want []byte
+	}{
+		{name: "nil", m: Map{"a": nil}, want: msgpackOf(0xc0)},
+		{name: "false", m: Map{"a": false}, want: msgpackOf(0xc2)},
+		{name: "true", m: Map{"a": true}, want: msgpackOf(0xc3)},
+		{name: "positive fixint", m: Map{"a": 1}, want: msgpackOf(0x01)},
+		{name: "uint8", m: Map{"a": 200}, want: msgpackOf(0xcc, 0xc8)},
+		{name: "uint16", m: Map{"a": 65535}, want: msgpackOf(0xcd, 0xff, 0xff)},
+		{name: "uint32", m: Map{"a": uint32(1 << 31)}, want: msgpackOf(0xce, 0x80, 0, 0, 0)},
+		{name: "uint64", m: Map{"a": int64(1 << 32)}, want: msgpackOf(0xcf, 0, 0, 0, 1, 0, 0, 0, 0)},
+		{name: "negative fixint", m: Map{"a": -1}, want: msgpackOf(0xff)},
+		{name: "int8", m: Map{"a": -33}, want: msgpackOf(0xd0, 0xdf)},
+		{name: "int16", m: Map{"a": -129}, want: msgpackOf(0xd1, 0xff, 0x7f)},
+		{name: "int32", m: Map{"a": -40000}, want: msgpackOf(0xd2, 0xff, 0xff, 0x63, 0xc0)},
+		{name: "int64", m: Map{"a": int64(math.MinInt64)}, want: msgpackOf(0xd3, 0x80, 0, 0, 0, 0, 0, 0, 0)},
+		{name: "float32", m: Map{"a": float32(1.5)}, want: msgpackOf(0xca, 0x3f, 0xc0, 0, 0)},
+		{name: "float64", m: Map{"a": 1.5}, want: msgpackOf(0xcb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0)},
+		{name: "fixstr", m: Map{"a": "hi"}, want: msgpackOf(0xa2, 'h', 'i')},
+		{name: "str8", m: Map{"a": string(bytes.Repeat([]byte{'x'}, 32))},
+			want: msgpackOf(append([]byte{0xd9, 32}, bytes.Repeat([]byte{'x'}, 32)...)...)},
+		{name: "bin8", m: Map{"a": []byte{1, 2}}, want: msgpackOf(0xc4, 0x02, 0x01, 0x02)},
+		{name: "fixarray", m: Map{"a": []interface{}{1, "b"}}, want: msgpackOf(0x92, 0x01, 0xa1, 'b')},
+		{name: "map array", m: Map{"a": []Map{{"b": 1}}}, want: msgpackOf(0x91, 0x81, 0xa1, 'b', 0x01)},
+		{name: "nested map", m: Map{"a": map[string]interface{}{"b": 1}}, want: msgpackOf(0x81, 0xa1, 'b', 0x01)},
+		{name: "sorted keys", m: Map{"b": 1, "a": 2}, want: []byte{0x82, 0xa1, 'a', 0x02, 0xa1, 'b', 0x01}},
+		{name: "timestamp32", m: Map{"a": time.Unix(1, 0)}, want: msgpackOf(0xd6, 0xff, 0, 0, 0, 1)},
+		{name: "timestamp64", m: Map{"a": time.Unix(1, 1)}, want: msgpackOf(0xd7, 0xff, 0, 0, 0, 4, 0, 0, 0, 1)},
+		{name: "timestamp96", m: Map{"a": time.Unix(1<<34, 0)},
+			want: msgpackOf(0xc7, 12, 0xff, 0, 0, 0, 0, 0, 0, 0, 4, 0, 0, 0, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.ToMsgPack()
+			if err != nil {
+				t.Fatalf("ToMsgPack() error = %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("ToMsgPack() = % x, want % x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMap_ParseMsgPack(t *testing.T) {
+	tests := []struct {
+		name    string
+		b       []byte
+		want    Map
+		wantErr error
+	}{
+		{name: "int", b: msgpackOf(0xd0, 0xdf), want: Map{"a": int64(-33)}},
+		{name: "uint64 overflows int64", b: msgpackOf(0xcf, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff),
+			want: Map{"a": uint64(math.MaxUint64)}},
+		{name: "float32", b: msgpackOf(0xca, 0x3f, 0xc0, 0, 0), want: Map{"a": float32(1.5)}},
+		{name: "str8", b: msgpackOf(0xd9, 0x02, 'h', 'i'), want: Map{"a": "hi"}},
+		{name: "bin8", b: msgpackOf(0xc4, 0x01, 0xaa), want: Map{"a": []byte{0xaa}}},
+		{name: "map16", b: []byte{0xde, 0x00, 0x01, 0xa1, 'a', 0x01}, want: Map{"a": int64(1)}},
+		{name: "array16", b: msgpackOf(0xdc, 0x00, 0x01, 0xc3), want: Map{"a": []interface{}{true}}},
+		{name: "map array", b: msgpackOf(0x91, 0x80), want: Map{"a": []Map{{}}}},
+		{name: "timestamp32", b: msgpackOf(0xd6, 0xff, 0, 0, 0, 1), want: Map{"a": time.Unix(1, 0)}},
+		{name: "timestamp64", b: msgpackOf(0xd7, 0xff, 0, 0, 0, 4, 0, 0, 0, 1), want: Map{"a": time.Unix(1, 1)}},
+		{name: "timestamp96", b: msgpackOf(0xc7, 12, 0xff, 0, 0, 0, 1, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff),
+			want: Map{"a": time.Unix(-1, 1)}},
+		{name: "other extension", b: msgpackOf(0xd4, 0x01, 0xaa), want: Map{"a": []byte{0xaa}}},
+		{name: "empty", b: nil, wantErr: ErrShortData},
+		{name: "truncated map", b: []byte{0x81, 0xa1, 'a'}, wantErr: ErrShortData},
+		{name: "truncated key", b: []byte{0x81, 0xa1}, wantErr: ErrShortData},
+		{name: "truncated str8", b: msgpackOf(0xd9, 0x05, 'x'), wantErr: ErrShortData},
+		{name: "truncated uint32", b: msgpackOf(0xce, 0x00), wantErr: ErrShortData},
+		{name: "str32 longer than data", b: msgpackOf(0xdb, 0xff, 0xff, 0xff, 0xff), wantErr: ErrShortData},
+		{name: "array32 longer than data", b: msgpackOf(0xdd, 0xff, 0xff, 0xff, 0xff), wantErr: ErrShortData},
+		{name: "truncated extension", b: msgpackOf(0xd7, 0xff, 0x00), wantErr: ErrShortData},
+		{name: "not a map", b: []byte{0x01}, wantErr: ErrUnsupportedType},
+		{name: "never used", b: msgpackOf(0xc1), wantErr: ErrUnsupportedType},
+		{name: "invalid timestamp", b: msgpackOf(0xd5, 0xff, 0x00, 0x00), wantErr: ErrUnsupportedType},
+		{name: "deep nesting", b: msgpackOf(bytes.Repeat([]byte{0x91}, maxDecodeDepth+1)...), wantErr: ErrMaxDepth},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New()
+			err := got.ParseMsgPack(tt.b)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseMsgPack() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseMsgPack() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseMsgPack() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,287 @@
+package gomap
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// EventOp the operation which changed the map
+type EventOp int
+
+const (
+	// EventSet the value is set by Set
+	EventSet EventOp = iota
+	// EventDelete the value is deleted by Delete
+	EventDelete
+	// EventMerge the value is set by Merge
+	EventMerge
+	// EventAppend the value is set or appended by Append
+	EventAppend
+	// EventReplaceJoin the value is set by ReplaceJoin
+	EventReplaceJoin
+)
+
+var eventOpNames = [...]string{"set", "delete", "merge", "append", "replace_join"}
+
+func (op EventOp) String() string {
+	if op < 0 || int(op) >= len(eventOpNames) {
+		return "unknown"
+	}
+	return eventOpNames[op]
+}
+
+// Event is the change of one dotted path, New is nil for EventDelete
+type Event struct {
+	Path string
+	Old  interface{}
+	New  interface{}
+	Op   EventOp
+}
+
+type watcher struct {
+	id      int
+	pattern []string
+	fn      func(e Event)
+}
+
+// pendingEvent is an event waiting for delivery with the watchers at the time of the change
+type pendingEvent struct {
+	event    Event
+	watchers []*watcher
+}
+
+// ObservableMap is a Map which notifies the watchers when the values are changed,
+// it is safe for concurrent use. The watchers are called in the order they are added,
+// after the change is done and outside of the lock, so they can call the methods of the map.
+// The events are delivered in the order the changes are done: one goroutine delivers at a time,
+// the events of the changes made meanwhile by the other goroutines or by the watchers are queued
+// and delivered by it, so a watcher may be called after the method which made the change returns.
+// The maps and slices are deep copied when they are written, read and sent in the events,
+// so the caller and the watchers can not change the stored values without the lock.
+// If a watcher panics, the other events are still delivered, then the panic is raised again
+type ObservableMap struct {
+	mu          sync.RWMutex
+	m           Map
+	watchers    []*watcher
+	nextID      int
+	queue       []pendingEvent
+	dispatching bool
+}
+
+// NewObservableMap create an ObservableMap with a deep copy of the values of maps
+func NewObservableMap(maps ...Map) *ObservableMap {
+	return &ObservableMap{m: copyTree(Merge(maps...)).(Map)}
+}
+
+// Watch call fn for the changes of the paths matched by pattern, it returns a function to stop watching.
+// The pattern is a dotted path where "*" matches any key of one level, a change matches
+// if it is under the pattern ("db.*" matches "db.pool.size") or it replaces a subtree
+// containing the pattern ("db.*" matches "db")
+func (o *ObservableMap) Watch(pattern string, fn func(e Event)) (cancel func()) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.nextID++
+	w := &watcher{id: o.nextID, pattern: strings.Split(pattern, "."), fn: fn}
+	o.watchers = append(o.watchers, w)
+	return func() {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		for i := range o.watchers {
+			if o.watchers[i].id == w.id {
+				o.watchers = append(o.watchers[:i:i], o.watchers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// call calls fn with e and returns the value of the panic of fn
+func (w *watcher) call(e Event) (r interface{}) {
+	defer func() {
+		r = recover()
+	}()
+	w.fn(e)
+	return nil
+}
+
+func (w *watcher) match(path []string) bool {
+	for i := 0; i < len(path) && i < len(w.pattern); i++ {
+		if w.pattern[i] != "*" && w.pattern[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// set set a copy of the value of key under the lock and record the event if the value is changed
+func (o *ObservableMap) set(events []Event, key string, v interface{}, op EventOp) []Event {
+	old := o.m.Get(key)
+	v = copyTree(v)
+	o.m.Set(key, v)
+	if reflect.DeepEqual(old, v) {
+		return events
+	}
+	return append(events, Event{Path: key, Old: copyTree(old), New: copyTree(v), Op: op})
+}
+
+// apply run fn under the lock and queue its events, then deliver the queued events after unlock
+// unless another call is delivering them
+func (o *ObservableMap) apply(fn func(events []Event) []Event) {
+	o.mu.Lock()
+	for _, e := range fn(nil) {
+		o.queue = append(o.queue, pendingEvent{event: e, watchers: o.watchers})
+	}
+	if o.dispatching {
+		o.mu.Unlock()
+		return
+	}
+	o.dispatching = true
+	var panicked interface{}
+	for len(o.queue) > 0 {
+		queue := o.queue
+		o.queue = nil
+		o.mu.Unlock()
+		for _, p := range queue {
+			path := strings.Split(p.event.Path, ".")
+			for _, w := range p.watchers {
+				if !w.match(path) {
+					continue
+				}
+				if r := w.call(p.event); r != nil && panicked == nil {
+					panicked = r
+				}
+			}
+		}
+		o.mu.Lock()
+	}
+	o.dispatching = false
+	o.mu.Unlock()
+	if panicked != nil {
+		panic(panicked)
+	}
+}
+
+// Set set value to the dotted key
+func (o *ObservableMap) Set(key string, v interface{}) *ObservableMap {
+	o.apply(func(events []Event) []Event {
+		return o.set(events, key, v, EventSet)
+	})
+	return o
+}
+
+// Delete delete key value if key is exist
+func (o *ObservableMap) Delete(key string) bool {
+	deleted := false
+	o.apply(func(events []Event) []Event {
+		old := o.m.Get(key)
+		if deleted = o.m.Delete(key); deleted {
+			events = append(events, Event{Path: key, Old: copyTree(old), Op: EventDelete})
+		}
+		return events
+	})
+	return deleted
+}
+
+// Merge set the values of maps, the newer value will replace the older value
+func (o *ObservableMap) Merge(maps ...Map) *ObservableMap {
+	o.apply(func(events []Event) []Event {
+		for _, p := range maps {
+			for _, k := range p.SortKeys() {
+				events = o.set(events, k, p[k], EventMerge)
+			}
+		}
+		return events
+	})
+	return o
+}
+
+// Append append source map, see Map.Append
+func (o *ObservableMap) Append(p Map) *ObservableMap {
+	o.apply(func(events []Event) []Event {
+		for _, k := range p.SortKeys() {
+			v := p[k]
+			if arr, b := o.m.Get(k).([]interface{}); b {
+				// copy the old array, so Old of the event is not changed by append
+				v = append(append([]interface{}{}, arr...), v)
+			}
+			events = o.set(events, k, v, EventAppend)
+		}
+		return events
+	})
+	return o
+}
+
+//ReplaceJoin insert map p with replace
+func (o *ObservableMap) ReplaceJoin(p Map) *ObservableMap {
+	o.apply(func(events []Event) []Event {
+		for _, k := range p.SortKeys() {
+			events = o.set(events, k, p[k], EventReplaceJoin)
+		}
+		return events
+	})
+	return o
+}
+
+// Get get interface from map without default,
+// the nested maps and slices are returned as deep copies
+func (o *ObservableMap) Get(key string) interface{} {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return copyTree(o.m.Get(key))
+}
+
+// GetD get interface from map with default
+func (o *ObservableMap) GetD(key string, d interface{}) interface{} {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	if v := o.m.GetD(key, nil); v != nil {
+		return copyTree(v)
+	}
+	return d
+}
+
+// GetString get string from map without default
+func (o *ObservableMap) GetString(key string) string {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.m.GetString(key)
+}
+
+// GetInt64D get int64 from map with default
+func (o *ObservableMap) GetInt64D(key string, d int64) int64 {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.m.GetInt64D(key, d)
+}
+
+//Has check if key exist
+func (o *ObservableMap) Has(key string) bool {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.m.Has(key)
+}
+
+//Clone deep copy the map
+func (o *ObservableMap) Clone() Map {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return copyTree(o.m).(Map)
+}
+
+//String transfer map to JSON string
+func (o *ObservableMap) String() string {
+	v, err := o.MarshalJSON()
+	if err != nil {
+		return ""
+	}
+	return string(v)
+}
+
+// MarshalJSON implements json.Marshaler
+func (o *ObservableMap) MarshalJSON() ([]byte, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return json.Marshal(o.m)
+}
@@ -0,0 +1,173 @@
+package gomap
+
+import (
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestObservableMap_Watch(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		change  func(o *ObservableMap)
+		want    []Event
+	}{
+		{
+			name:    "set",
+			pattern: "db.*",
+			change:  func(o *ObservableMap) { o.Set("db.host", "y").Set("app", 1) },
+			want:    []Event{{Path: "db.host", Old: "x", New: "y", Op: EventSet}},
+		},
+		{
+			name:    "unchanged",
+			pattern: "db.*",
+			change:  func(o *ObservableMap) { o.Set("db.host", "x") },
+		},
+		{
+			name:    "parent replaced",
+			pattern: "db.host",
+			change:  func(o *ObservableMap) { o.Set("db", Map{"host": "y"}) },
+			want:    []Event{{Path: "db", Old: Map{"host": "x"}, New: Map{"host": "y"}, Op: EventSet}},
+		},
+		{
+			name:    "delete",
+			pattern: "*",
+			change:  func(o *ObservableMap) { o.Delete("db") },
+			want:    []Event{{Path: "db", Old: Map{"host": "x"}, Op: EventDelete}},
+		},
+		{
+			name:    "merge in sorted order",
+			pattern: "*",
+			change:  func(o *ObservableMap) { o.Merge(Map{"b": 2, "a": 1}) },
+			want:    []Event{{Path: "a", New: 1, Op: EventMerge}, {Path: "b", New: 2, Op: EventMerge}},
+		},
+		{
+			name:    "append",
+			pattern: "l",
+			change:  func(o *ObservableMap) { o.Append(Map{"l": 2}) },
+			want:    []Event{{Path: "l", Old: []interface{}{1}, New: []interface{}{1, 2}, Op: EventAppend}},
+		},
+		{
+			name:    "cancelled",
+			pattern: "-",
+			change:  func(o *ObservableMap) { o.Set("db.host", "y") },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := NewObservableMap(Map{"db": Map{"host": "x"}, "l": []interface{}{1}})
+			var got []Event
+			cancel := o.Watch(tt.pattern, func(e Event) {
+				got = append(got, e)
+			})
+			if tt.pattern == "-" {
+				cancel = o.Watch("*", func(e Event) {
+					got = append(got, e)
+				})
+				cancel()
+			}
+			tt.change(o)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("events = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestObservableMap_Copy(t *testing.T) {
+	v := Map{"a": Map{"b": 1}}
+	o := NewObservableMap(Map{"v": v})
+	o.Watch("*", func(e Event) {
+		e.New.(Map)["b"] = "watcher"
+	})
+	v["a"].(Map)["b"] = 2
+	o.Get("v.a").(Map)["b"] = 3
+	o.GetD("v", nil).(Map)["a"] = nil
+	o.Clone()["v"].(Map)["a"] = nil
+	w := Map{"b": 1}
+	o.Set("w", w)
+	w["b"] = 2
+	want := Map{"v": Map{"a": Map{"b": 1}}, "w": Map{"b": 1}}
+	if got := o.Clone(); !reflect.DeepEqual(got, want) {
+		t.Errorf("stored value = %v, want %v", got, want)
+	}
+}
+
+func TestObservableMap_Panic(t *testing.T) {
+	o := NewObservableMap()
+	var got []string
+	o.Watch("*", func(e Event) {
+		if e.Path == "a" {
+			panic("watcher")
+		}
+	})
+	o.Watch("*", func(e Event) {
+		got = append(got, e.Path)
+	})
+	func() {
+		defer func() {
+			if r := recover(); r != "watcher" {
+				t.Errorf("recover() = %v, want watcher", r)
+			}
+		}()
+		o.Merge(Map{"a": 1, "b": 2})
+	}()
+	o.Set("c", 3)
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+}
+
+// run with -race, the events are delivered in the order of the changes
+// while the callers and the watchers use their values
+func TestObservableMap_Concurrent(t *testing.T) {
+	o := NewObservableMap()
+	var mu sync.Mutex
+	last := -1
+	ordered := true
+	o.Watch("n", func(e Event) {
+		n := e.New.(Map)["n"].(int)
+		mu.Lock()
+		if n <= last {
+			ordered = false
+		}
+		last = n
+		mu.Unlock()
+		e.New.(Map)["n"] = -1
+	})
+	o.Watch("*", func(e Event) {
+		if e.Path == "w" {
+			o.Set("r", o.Get("w"))
+		}
+	})
+	counter := 0
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				o.apply(func(events []Event) []Event {
+					counter++
+					return o.set(events, "n", Map{"n": counter}, EventSet)
+				})
+				v := Map{"i": i, "j": j}
+				o.Set("w", v)
+				v["i"] = -1
+				if got, b := o.Get("r").(Map); b {
+					got["i"] = -1
+				}
+				_ = o.String() + strconv.FormatInt(o.Clone().GetMap("n").GetInt64D("n", 0), 10)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if !ordered {
+		t.Errorf("events are not delivered in the order of the changes")
+	}
+	if last != 8*100 {
+		t.Errorf("last event = %v, want %v", last, 8*100)
+	}
+}
@@ -0,0 +1,249 @@
+package gomap
+
+import (
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ProjectSetting defines how Project treats the patterns which match nothing
+type ProjectSetting struct {
+	// OmitMissing skip the missing keys, otherwise nil is set for the patterns without wildcard like Only,
+	// except the keys under a value which is not a map, such as "a.b" when "a" is 1
+	OmitMissing bool
+}
+
+// ProjectOption ...
+type ProjectOption func(op *ProjectSetting)
+
+// ProjectOmitMissing enable or disable the nil values of the missing keys
+func ProjectOmitMissing(b bool) ProjectOption {
+	return func(op *ProjectSetting) {
+		op.OmitMissing = b
+	}
+}
+
+// Project returns a copy of the values matched by the dotted patterns, such as the field masks "id,profile.name".
+// In a pattern "*" matches one key, "**" matches any number of keys, and the other glob syntax of path.Match
+// can be used in a key like "pass*". An array is matched by the indexes with "0" or "*",
+// and a plain key is applied to every element, so "users.email" is the same as "users.*.email"
+func (m Map) Project(patterns []string, opts ...ProjectOption) Map {
+	setting := ProjectSetting{}
+	for i := range opts {
+		opts[i](&setting)
+	}
+	ps := splitPatterns(patterns)
+	p := New()
+	if v, b := projectValue(m, ps, true); b {
+		p = v.(Map)
+	}
+	if setting.OmitMissing {
+		return p
+	}
+	// the shorter paths first, so the result of "a" and "a.b" does not depend on their order
+	literals := make([][]string, 0, len(ps))
+	for _, keys := range ps {
+		if isLiteral(keys) {
+			literals = append(literals, keys)
+		}
+	}
+	sort.SliceStable(literals, func(i, j int) bool {
+		return len(literals[i]) < len(literals[j])
+	})
+	for _, keys := range literals {
+		if p.HasPath(keys) || underValue(m, keys) {
+			continue
+		}
+		if _, b := projectValue(m, [][]string{keys}, true); !b {
+			// the path is under a nil set by a shorter pattern if it fails
+			_ = setPathStrict(p, keys, nil)
+		}
+	}
+	return p
+}
+
+// underValue returns true if a parent of keys in m is not a map
+func underValue(m Map, keys []string) bool {
+	var node interface{} = m
+	for _, k := range keys[:len(keys)-1] {
+		sub, b := toMap(node)
+		if !b {
+			return true
+		}
+		v, exists := sub[k]
+		if !exists {
+			return false
+		}
+		node = v
+	}
+	_, b := toMap(node)
+	return !b
+}
+
+// Except returns a copy of map without the values matched by the dotted patterns, see Project for the patterns
+func (m Map) Except(patterns []string) Map {
+	v, _ := projectValue(m, splitPatterns(patterns), false)
+	return v.(Map)
+}
+
+func splitPatterns(patterns []string) [][]string {
+	ps := make([][]string, 0, len(patterns))
+	for _, p := range patterns {
+		if p = strings.TrimSpace(p); p != "" {
+			ps = append(ps, strings.Split(p, "."))
+		}
+	}
+	return ps
+}
+
+func isWildcard(key string) bool {
+	return strings.ContainsAny(key, `*?[\`)
+}
+
+func isLiteral(keys []string) bool {
+	for _, k := range keys {
+		if isWildcard(k) {
+			return false
+		}
+	}
+	return true
+}
+
+func isIndex(key string) bool {
+	_, err := strconv.Atoi(key)
+	return err == nil
+}
+
+func matchKey(pattern, key string) bool {
+	if pattern == "*" || pattern == key {
+		return true
+	}
+	b, err := path.Match(pattern, key)
+	return err == nil && b
+}
+
+// closure adds the patterns which "**" matches no key
+func closure(ps [][]string) [][]string {
+	out := make([][]string, 0, len(ps))
+	for _, p := range ps {
+		out = append(out, p)
+		for len(p) > 0 && p[0] == "**" {
+			p = p[1:]
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// advance returns the rest of the patterns after matching key, and whether a pattern ends at key
+func advance(ps [][]string, key string, index bool) ([][]string, bool) {
+	var next [][]string
+	done := false
+	for _, p := range ps {
+		if len(p) == 0 {
+			continue
+		}
+		var rest [][]string
+		switch {
+		case p[0] == "**":
+			rest = [][]string{p}
+		case matchKey(p[0], key):
+			rest = [][]string{p[1:]}
+		case index && !isWildcard(p[0]) && !isIndex(p[0]):
+			// a plain key is applied to every element of array
+			rest = [][]string{p}
+		default:
+		}
+		for _, r := range closure(rest) {
+			if len(r) == 0 {
+				done = true
+			} else {
+				next = append(next, r)
+			}
+		}
+	}
+	return next, done
+}
+
+// projectValue returns the values matched by ps if include is true, or the values not matched,
+// the bool is false if nothing is left
+func projectValue(value interface{}, ps [][]string, include bool) (interface{}, bool) {
+	ps = closure(ps)
+	v := project(value, ps, include)
+	if !include {
+		return v, true
+	}
+	if v == nil {
+		return nil, false
+	}
+	return v, true
+}
+
+func project(value interface{}, ps [][]string, include bool) interface{} {
+	child := func(key string, v interface{}, index bool) (interface{}, bool) {
+		next, done := advance(ps, key, index)
+		if done {
+			if include {
+				return copyTree(v), true
+			}
+			return nil, false
+		}
+		if len(next) == 0 {
+			if include {
+				return nil, false
+			}
+			return copyTree(v), true
+		}
+		sub := project(v, next, include)
+		if include && sub == nil {
+			return nil, false
+		}
+		return sub, true
+	}
+	switch v := value.(type) {
+	case Map:
+		p := make(Map, len(v))
+		for k := range v {
+			if sub, b := child(k, v[k], false); b {
+				p[k] = sub
+			}
+		}
+		if include && len(p) == 0 {
+			return nil
+		}
+		return p
+	case map[string]interface{}:
+		return project(Map(v), ps, include)
+	case []Map:
+		arr := make([]Map, 0, len(v))
+		for i := range v {
+			if sub, b := child(strconv.Itoa(i), v[i], true); b {
+				if sm, b := sub.(Map); b {
+					arr = append(arr, sm)
+				}
+			}
+		}
+		if include && len(arr) == 0 {
+			return nil
+		}
+		return arr
+	case []interface{}:
+		arr := make([]interface{}, 0, len(v))
+		for i := range v {
+			if sub, b := child(strconv.Itoa(i), v[i], true); b {
+				arr = append(arr, sub)
+			}
+		}
+		if include && len(arr) == 0 {
+			return nil
+		}
+		return arr
+	default:
+	}
+	if include {
+		// the patterns are left, so the leaf is not matched
+		return nil
+	}
+	return value
+}
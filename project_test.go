@@ -0,0 +1,57 @@
+package gomap
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMap_Project(t *testing.T) {
+	src := Map{
+		"id":    1,
+		"name":  Map{"first": "a", "last": "b"},
+		"users": []Map{{"email": "x", "pass": "1"}, {"email": "y", "pass": "2"}},
+		"tags":  []interface{}{"t1", "t2"},
+		"empty": nil,
+	}
+	tests := []struct {
+		name     string
+		patterns []string
+		opts     []ProjectOption
+		want     Map
+	}{
+		{name: "keys", patterns: []string{"id", "name.first"}, want: Map{"id": 1, "name": Map{"first": "a"}}},
+		{name: "wildcard", patterns: []string{"name.*"}, want: Map{"name": Map{"first": "a", "last": "b"}}},
+		{name: "array elements", patterns: []string{"users.email"},
+			want: Map{"users": []Map{{"email": "x"}, {"email": "y"}}}},
+		{name: "array index", patterns: []string{"users.1.pass", "tags.0"},
+			want: Map{"users": []Map{{"pass": "2"}}, "tags": []interface{}{"t1"}}},
+		{name: "glob", patterns: []string{"**.pass*"},
+			want: Map{"users": []Map{{"pass": "1"}, {"pass": "2"}}}},
+		{name: "missing", patterns: []string{"x.y"}, want: Map{"x": Map{"y": nil}}},
+		{name: "omit missing", patterns: []string{"x.y"}, opts: []ProjectOption{ProjectOmitMissing(true)}, want: Map{}},
+		{name: "overlapping value", patterns: []string{"id", "id.x"}, want: Map{"id": 1}},
+		{name: "overlapping value reversed", patterns: []string{"id.x", "id"}, want: Map{"id": 1}},
+		{name: "under a value", patterns: []string{"id.x"}, want: Map{}},
+		{name: "under nil", patterns: []string{"empty.x"}, want: Map{}},
+		{name: "overlapping map", patterns: []string{"name", "name.first"},
+			want: Map{"name": Map{"first": "a", "last": "b"}}},
+		{name: "overlapping missing", patterns: []string{"x.y", "x"}, want: Map{"x": nil}},
+		{name: "missing key of map", patterns: []string{"name.middle", "name.first"},
+			want: Map{"name": Map{"first": "a", "middle": nil}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := src.Project(tt.patterns, tt.opts...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Project() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMap_Except(t *testing.T) {
+	src := Map{"id": 1, "users": []Map{{"email": "x", "pass": "1"}}}
+	want := Map{"id": 1, "users": []Map{{"email": "x"}}}
+	if got := src.Except([]string{"users.pass", "missing.key"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Except() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,296 @@
+package gomap
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+)
+
+// ErrInvalidWire ...
+var ErrInvalidWire = errors.New("invalid protobuf wire data")
+
+// the wire types of protobuf
+const (
+	protoVarint  = 0
+	protoFixed64 = 1
+	protoBytes   = 2
+	protoFixed32 = 5
+)
+
+// the field numbers of google.protobuf.Value
+const (
+	protoNullValue   = 1
+	protoNumberValue = 2
+	protoStringValue = 3
+	protoBoolValue   = 4
+	protoStructValue = 5
+	protoListValue   = 6
+)
+
+//ToProtoStruct transfer map to the wire format of google.protobuf.Struct,
+// the numbers are written as double, []byte as base64 string and time.Time as RFC 3339 string
+func (m Map) ToProtoStruct() ([]byte, error) {
+	b, err := appendProtoStruct(nil, m)
+	if err != nil {
+		return nil, fmt.Errorf("map to proto struct error:%w", err)
+	}
+	return b, nil
+}
+
+//ParseProtoStruct parse the wire format of google.protobuf.Struct to map,
+// the structs are decoded to Map, the lists to []interface{} ([]Map if all elements are Map)
+// and the numbers to float64
+func (m Map) ParseProtoStruct(b []byte) error {
+	v, err := parseProtoStruct(b, 0)
+	if err != nil {
+		return fmt.Errorf("proto struct to map error:%w", err)
+	}
+	for k := range v {
+		m[k] = v[k]
+	}
+	return nil
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
+
+func appendProtoTag(b []byte, field int, wire int) []byte {
+	return appendUvarint(b, uint64(field)<<3|uint64(wire))
+}
+
+func appendProtoBytes(b []byte, field int, v []byte) []byte {
+	b = appendProtoTag(b, field, protoBytes)
+	b = appendUvarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+func appendProtoStruct(b []byte, m Map) ([]byte, error) {
+	for _, k := range m.SortKeys() {
+		value, err := appendProtoValue(nil, m[k])
+		if err != nil {
+			return nil, err
+		}
+		// FieldsEntry { string key = 1; Value value = 2; }
+		entry := appendProtoBytes(nil, 1, []byte(k))
+		entry = appendProtoBytes(entry, 2, value)
+		b = appendProtoBytes(b, 1, entry)
+	}
+	return b, nil
+}
+
+func appendProtoNumber(b []byte, f float64) []byte {
+	b = appendProtoTag(b, protoNumberValue, protoFixed64)
+	return appendUint64LE(b, math.Float64bits(f))
+}
+
+func appendUint64LE(b []byte, v uint64) []byte {
+	return append(b, byte(v), byte(v>>8), byte(v>>16), byte(v>>24),
+		byte(v>>32), byte(v>>40), byte(v>>48), byte(v>>56))
+}
+
+func appendProtoValue(b []byte, value interface{}) ([]byte, error) {
+	if m, ok := toMap(value); ok {
+		s, err := appendProtoStruct(nil, m)
+		if err != nil {
+			return nil, err
+		}
+		return appendProtoBytes(b, protoStructValue, s), nil
+	}
+	switch v := value.(type) {
+	case nil:
+		b = appendProtoTag(b, protoNullValue, protoVarint)
+		return append(b, 0), nil
+	case bool:
+		b = appendProtoTag(b, protoBoolValue, protoVarint)
+		if v {
+			return append(b, 1), nil
+		}
+		return append(b, 0), nil
+	case string:
+		return appendProtoBytes(b, protoStringValue, []byte(v)), nil
+	case []byte:
+		return appendProtoBytes(b, protoStringValue, []byte(base64.StdEncoding.EncodeToString(v))), nil
+	case time.Time:
+		return appendProtoBytes(b, protoStringValue, []byte(v.Format(time.RFC3339Nano))), nil
+	default:
+	}
+	if f, ok := ParseNumber(value); ok {
+		return appendProtoNumber(b, f), nil
+	}
+	if i, ok := ParseInt(value); ok {
+		return appendProtoNumber(b, float64(i)), nil
+	}
+	if arr, ok := toArray(value); ok {
+		// ListValue { repeated Value values = 1; }
+		var list []byte
+		for i := range arr {
+			elem, err := appendProtoValue(nil, arr[i])
+			if err != nil {
+				return nil, err
+			}
+			list = appendProtoBytes(list, 1, elem)
+		}
+		return appendProtoBytes(b, protoListValue, list), nil
+	}
+	v, err := reflectValue(reflect.ValueOf(value))
+	if err != nil {
+		return nil, err
+	}
+	return appendProtoValue(b, v)
+}
+
+// protoField is a field read from the wire data
+type protoField struct {
+	num   int
+	wire  int
+	value uint64
+	bytes []byte
+}
+
+// readProtoFields reads all the fields of message b
+func readProtoFields(b []byte, fn func(f protoField) error) error {
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			return ErrInvalidWire
+		}
+		b = b[n:]
+		f := protoField{num: int(tag >> 3), wire: int(tag & 7)}
+		switch f.wire {
+		case protoVarint:
+			f.value, n = binary.Uvarint(b)
+			if n <= 0 {
+				return ErrInvalidWire
+			}
+			b = b[n:]
+		case protoFixed64:
+			if len(b) < 8 {
+				return ErrInvalidWire
+			}
+			f.value = binary.LittleEndian.Uint64(b)
+			b = b[8:]
+		case protoFixed32:
+			if len(b) < 4 {
+				return ErrInvalidWire
+			}
+			f.value = uint64(binary.LittleEndian.Uint32(b))
+			b = b[4:]
+		case protoBytes:
+			l, n := binary.Uvarint(b)
+			if n <= 0 || l > uint64(len(b)-n) {
+				return ErrInvalidWire
+			}
+			f.bytes = b[n : n+int(l)]
+			b = b[n+int(l):]
+		default:
+			return fmt.Errorf("%w: wire type %d", ErrInvalidWire, f.wire)
+		}
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseProtoStruct parses a Struct at the nesting depth of the structs and lists
+func parseProtoStruct(b []byte, depth int) (Map, error) {
+	if depth >= maxDecodeDepth {
+		return nil, ErrMaxDepth
+	}
+	m := New()
+	err := readProtoFields(b, func(f protoField) error {
+		if f.num != 1 || f.wire != protoBytes {
+			return nil
+		}
+		var key string
+		var value interface{}
+		err := readProtoFields(f.bytes, func(e protoField) error {
+			if e.wire != protoBytes {
+				return nil
+			}
+			switch e.num {
+			case 1:
+				key = string(e.bytes)
+			case 2:
+				v, err := parseProtoValue(e.bytes, depth)
+				if err != nil {
+					return err
+				}
+				value = v
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		m[key] = value
+		return nil
+	})
+	return m, err
+}
+
+// protoWireTypes are the wire types of the fields of google.protobuf.Value
+var protoWireTypes = map[int]int{
+	protoNullValue:   protoVarint,
+	protoNumberValue: protoFixed64,
+	protoStringValue: protoBytes,
+	protoBoolValue:   protoVarint,
+	protoStructValue: protoBytes,
+	protoListValue:   protoBytes,
+}
+
+func parseProtoValue(b []byte, depth int) (interface{}, error) {
+	var value interface{}
+	err := readProtoFields(b, func(f protoField) error {
+		if wire, ok := protoWireTypes[f.num]; ok && wire != f.wire {
+			return fmt.Errorf("%w: wire type %d of field %d", ErrInvalidWire, f.wire, f.num)
+		}
+		var err error
+		switch f.num {
+		case protoNullValue:
+			value = nil
+		case protoNumberValue:
+			value = math.Float64frombits(f.value)
+		case protoStringValue:
+			value = string(f.bytes)
+		case protoBoolValue:
+			value = f.value != 0
+		case protoStructValue:
+			value, err = parseProtoStruct(f.bytes, depth+1)
+		case protoListValue:
+			value, err = parseProtoList(f.bytes, depth+1)
+		default:
+		}
+		return err
+	})
+	return value, err
+}
+
+func parseProtoList(b []byte, depth int) (interface{}, error) {
+	if depth >= maxDecodeDepth {
+		return nil, ErrMaxDepth
+	}
+	arr := []interface{}{}
+	err := readProtoFields(b, func(f protoField) error {
+		if f.num != 1 || f.wire != protoBytes {
+			return nil
+		}
+		v, err := parseProtoValue(f.bytes, depth)
+		if err != nil {
+			return err
+		}
+		arr = append(arr, v)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toMapArray(arr), nil
+}
@@ -0,0 +1,104 @@
+package gomap
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// protoOf returns the bytes of the Struct {"a": v} with the encoded Value v
+func protoOf(v ...byte) []byte {
+	return append([]byte{0x0a, byte(5 + len(v)), 0x0a, 0x01, 'a', 0x12, byte(len(v))}, v...)
+}
+
+func TestMap_ToProtoStruct(t *testing.T) {
+	tests := []struct {
+		name string
+		m    Map
+		want []byte
+	}{
+		{name: "empty", m: Map{}, want: nil},
+		{name: "null", m: Map{"a": nil}, want: protoOf(0x08, 0x00)},
+		{name: "number", m: Map{"a": 1.5}, want: protoOf(0x11, 0, 0, 0, 0, 0, 0, 0xf8, 0x3f)},
+		{name: "int", m: Map{"a": 1}, want: protoOf(0x11, 0, 0, 0, 0, 0, 0, 0xf0, 0x3f)},
+		{name: "string", m: Map{"a": "hi"}, want: protoOf(0x1a, 0x02, 'h', 'i')},
+		{name: "bytes", m: Map{"a": []byte{1, 2}}, want: protoOf(0x1a, 0x04, 'A', 'Q', 'I', '=')},
+		{name: "false", m: Map{"a": false}, want: protoOf(0x20, 0x00)},
+		{name: "true", m: Map{"a": true}, want: protoOf(0x20, 0x01)},
+		{name: "struct", m: Map{"a": Map{"b": true}},
+			want: protoOf(0x2a, 0x09, 0x0a, 0x07, 0x0a, 0x01, 'b', 0x12, 0x02, 0x20, 0x01)},
+		{name: "list", m: Map{"a": []interface{}{1, "x"}},
+			want: protoOf(0x32, 0x10, 0x0a, 0x09, 0x11, 0, 0, 0, 0, 0, 0, 0xf0, 0x3f, 0x0a, 0x03, 0x1a, 0x01, 'x')},
+		{name: "sorted keys", m: Map{"b": true, "a": false},
+			want: append(protoOf(0x20, 0x00), 0x0a, 0x07, 0x0a, 0x01, 'b', 0x12, 0x02, 0x20, 0x01)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.ToProtoStruct()
+			if err != nil {
+				t.Fatalf("ToProtoStruct() error = %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("ToProtoStruct() = % x, want % x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMap_ParseProtoStruct(t *testing.T) {
+	// the lists nested deeper than maxDecodeDepth
+	deep := []byte{0x08, 0x00}
+	for i := 0; i <= maxDecodeDepth; i++ {
+		deep = appendProtoBytes(nil, protoListValue, appendProtoBytes(nil, 1, deep))
+	}
+	deep = appendProtoBytes(nil, 1, appendProtoBytes(appendProtoBytes(nil, 1, []byte("a")), 2, deep))
+	tests := []struct {
+		name    string
+		b       []byte
+		want    Map
+		wantErr error
+	}{
+		{name: "empty", b: nil, want: Map{}},
+		{name: "null", b: protoOf(0x08, 0x00), want: Map{"a": nil}},
+		{name: "number", b: protoOf(0x11, 0, 0, 0, 0, 0, 0, 0xf8, 0x3f), want: Map{"a": 1.5}},
+		{name: "string", b: protoOf(0x1a, 0x02, 'h', 'i'), want: Map{"a": "hi"}},
+		{name: "bool", b: protoOf(0x20, 0x01), want: Map{"a": true}},
+		{name: "struct", b: protoOf(0x2a, 0x09, 0x0a, 0x07, 0x0a, 0x01, 'b', 0x12, 0x02, 0x20, 0x01),
+			want: Map{"a": Map{"b": true}}},
+		{name: "list", b: protoOf(0x32, 0x10, 0x0a, 0x09, 0x11, 0, 0, 0, 0, 0, 0, 0xf0, 0x3f, 0x0a, 0x03, 0x1a, 0x01, 'x'),
+			want: Map{"a": []interface{}{1.0, "x"}}},
+		{name: "list of structs", b: protoOf(0x32, 0x04, 0x0a, 0x02, 0x2a, 0x00), want: Map{"a": []Map{{}}}},
+		{name: "unknown field", b: append(protoOf(0x20, 0x01), 0x10, 0x01), want: Map{"a": true}},
+		{name: "bad tag varint", b: []byte{0x80}, wantErr: ErrInvalidWire},
+		{name: "bad value varint", b: protoOf(0x20, 0x80), wantErr: ErrInvalidWire},
+		{name: "length past the end", b: []byte{0x0a, 0x05, 0x0a}, wantErr: ErrInvalidWire},
+		{name: "huge length", b: []byte{0x0a, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}, wantErr: ErrInvalidWire},
+		{name: "truncated fixed64", b: protoOf(0x11, 0x00, 0x00), wantErr: ErrInvalidWire},
+		{name: "truncated fixed32", b: []byte{0x0d, 0x00}, wantErr: ErrInvalidWire},
+		{name: "start group", b: []byte{0x0b}, wantErr: ErrInvalidWire},
+		{name: "end group", b: protoOf(0x0c), wantErr: ErrInvalidWire},
+		{name: "number as varint", b: protoOf(0x10, 0x01), wantErr: ErrInvalidWire},
+		{name: "string as varint", b: protoOf(0x18, 0x01), wantErr: ErrInvalidWire},
+		{name: "struct as fixed32", b: protoOf(0x2d, 0, 0, 0, 0), wantErr: ErrInvalidWire},
+		{name: "deep nesting", b: deep, wantErr: ErrMaxDepth},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New()
+			err := got.ParseProtoStruct(tt.b)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseProtoStruct() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseProtoStruct() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseProtoStruct() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,304 @@
+package gomap
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// RedactMode is how the sensitive values are replaced
+type RedactMode int
+
+const (
+	// RedactMask replace the value with the mask
+	RedactMask RedactMode = iota
+	// RedactHash replace the value with "hmac-sha256:" and the hex of its HMAC-SHA256 keyed by HashKey,
+	// so the same values can be correlated in the logs, the values are masked if there is no HashKey
+	RedactHash
+	// RedactTruncate keep the last Keep characters of the value after the mask, such as "******1234"
+	RedactTruncate
+)
+
+// RedactSetting defines which values are sensitive and how they are replaced
+type RedactSetting struct {
+	Mode RedactMode
+	Mask string
+	// HashKey is the key of the HMAC of RedactHash
+	HashKey []byte
+	// Keep is the number of the characters kept by RedactTruncate
+	Keep int
+	// Keys are the glob patterns of the key names matched in lower case, such as "*token*"
+	Keys []string
+	// MaskKeys are the glob patterns of the key names like Keys, but their values are always
+	// replaced with Mask whatever the Mode is, such as "*password*"
+	MaskKeys []string
+	// Values are the expressions of the sensitive text in the string values, only the matched text is replaced
+	Values []*regexp.Regexp
+	// Cards replace the card numbers of 13 to 19 digits which pass the Luhn check,
+	// in the string values and the integer values
+	Cards bool
+}
+
+// RedactOption ...
+type RedactOption func(op *RedactSetting)
+
+// cardPattern matches the card numbers of 13 to 19 digits, separated by spaces or dashes
+var cardPattern = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+
+// defaultRedactSetting returns the setting used by Redact when there is no option and by LogValue
+func defaultRedactSetting() *RedactSetting {
+	return &RedactSetting{
+		Mode: RedactMask,
+		Mask: "******",
+		Keep: 4,
+		Keys: []string{"*token*", "*api_key*", "*apikey*", "authorization", "cookie",
+			"*card_number*", "*cardnumber*"},
+		MaskKeys: []string{"*password*", "*passwd*", "*secret*", "cvv", "cvc"},
+		Cards:    true,
+	}
+}
+
+// RedactWithMode set how the values are replaced
+func RedactWithMode(mode RedactMode) RedactOption {
+	return func(op *RedactSetting) {
+		op.Mode = mode
+	}
+}
+
+// RedactWithHash replace the values by RedactHash with the HMAC key
+func RedactWithHash(key []byte) RedactOption {
+	return func(op *RedactSetting) {
+		op.Mode = RedactHash
+		op.HashKey = key
+	}
+}
+
+// RedactMaskText set the mask
+func RedactMaskText(mask string) RedactOption {
+	return func(op *RedactSetting) {
+		op.Mask = mask
+	}
+}
+
+// RedactKeep set the number of the characters kept by RedactTruncate, the negative n is 0
+func RedactKeep(n int) RedactOption {
+	return func(op *RedactSetting) {
+		if n < 0 {
+			n = 0
+		}
+		op.Keep = n
+	}
+}
+
+// RedactKeys set the glob patterns of the key names, it replaces the default patterns
+func RedactKeys(keys ...string) RedactOption {
+	return func(op *RedactSetting) {
+		op.Keys = keys
+	}
+}
+
+// RedactMaskKeys set the glob patterns of the key names which values are always masked,
+// it replaces the default patterns
+func RedactMaskKeys(keys ...string) RedactOption {
+	return func(op *RedactSetting) {
+		op.MaskKeys = keys
+	}
+}
+
+// RedactValues set the expressions of the sensitive text
+func RedactValues(values ...*regexp.Regexp) RedactOption {
+	return func(op *RedactSetting) {
+		op.Values = values
+	}
+}
+
+// RedactCards enable or disable the replacing of the card numbers
+func RedactCards(b bool) RedactOption {
+	return func(op *RedactSetting) {
+		op.Cards = b
+	}
+}
+
+func newRedactSetting(opts ...RedactOption) *RedactSetting {
+	setting := defaultRedactSetting()
+	for i := range opts {
+		opts[i](setting)
+	}
+	return setting
+}
+
+// Redact returns a deep copy of map with the sensitive values replaced, the values are sensitive
+// if their paths are matched by the dotted patterns (see Project for the patterns),
+// or their key names are matched by the Keys or the MaskKeys of setting. The maps and arrays under
+// a sensitive key keep their shape and all their values are replaced
+func (m Map) Redact(patterns []string, opts ...RedactOption) Map {
+	setting := newRedactSetting(opts...)
+	return setting.redact(m, closure(splitPatterns(patterns))).(Map)
+}
+
+// matchKeys check the key name is matched by one of patterns in lower case
+func matchKeys(patterns []string, key string) bool {
+	key = strings.ToLower(key)
+	for _, p := range patterns {
+		if matchKey(p, key) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *RedactSetting) redact(value interface{}, ps [][]string) interface{} {
+	child := func(key string, v interface{}, index bool) interface{} {
+		if !index && matchKeys(s.MaskKeys, key) {
+			return s.replaceAll(v, s.mask)
+		}
+		next, done := advance(ps, key, index)
+		if done || !index && matchKeys(s.Keys, key) {
+			return s.replaceAll(v, s.replace)
+		}
+		return s.redact(v, next)
+	}
+	switch v := value.(type) {
+	case Map:
+		m := make(Map, len(v))
+		for k := range v {
+			m[k] = child(k, v[k], false)
+		}
+		return m
+	case map[string]interface{}:
+		return s.redact(Map(v), ps)
+	case []Map, []interface{}:
+		return s.redactArray(v, child)
+	case string:
+		if s.Cards {
+			v = cardPattern.ReplaceAllStringFunc(v, func(text string) string {
+				if !isCardNumber(text) {
+					return text
+				}
+				return s.replace(text)
+			})
+		}
+		for _, re := range s.Values {
+			v = re.ReplaceAllStringFunc(v, s.replace)
+		}
+		return v
+	case int, int64, int32, uint, uint64, uint32, float64, json.Number:
+		if text := formatScalar(v); s.Cards && isCardNumber(text) {
+			return s.replace(text)
+		}
+	default:
+	}
+	return value
+}
+
+// isCardNumber check text is 13 to 19 digits which pass the Luhn check, the spaces and dashes are skipped
+func isCardNumber(text string) bool {
+	sum, n := 0, 0
+	for i := len(text) - 1; i >= 0; i-- {
+		c := text[i]
+		if c == ' ' || c == '-' {
+			continue
+		}
+		if c < '0' || c > '9' {
+			return false
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && n <= 19 && sum%10 == 0
+}
+
+func (s *RedactSetting) redactArray(value interface{}, child func(key string, v interface{}, index bool) interface{}) interface{} {
+	switch v := value.(type) {
+	case []Map:
+		arr := make([]Map, len(v))
+		for i := range v {
+			arr[i], _ = child(strconv.Itoa(i), v[i], true).(Map)
+		}
+		return arr
+	case []interface{}:
+		arr := make([]interface{}, len(v))
+		for i := range v {
+			arr[i] = child(strconv.Itoa(i), v[i], true)
+		}
+		return arr
+	default:
+	}
+	return value
+}
+
+// replaceAll replace all the values of the tree by fn and keep the maps and arrays,
+// the values of MaskKeys are masked
+func (s *RedactSetting) replaceAll(value interface{}, fn func(text string) string) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case Map:
+		m := make(Map, len(v))
+		for k := range v {
+			if matchKeys(s.MaskKeys, k) {
+				m[k] = s.replaceAll(v[k], s.mask)
+				continue
+			}
+			m[k] = s.replaceAll(v[k], fn)
+		}
+		return m
+	case map[string]interface{}:
+		return s.replaceAll(Map(v), fn)
+	case []Map:
+		arr := make([]Map, len(v))
+		for i := range v {
+			arr[i] = s.replaceAll(v[i], fn).(Map)
+		}
+		return arr
+	case []interface{}:
+		arr := make([]interface{}, len(v))
+		for i := range v {
+			arr[i] = s.replaceAll(v[i], fn)
+		}
+		return arr
+	default:
+	}
+	return fn(formatScalar(value))
+}
+
+func (s *RedactSetting) mask(string) string {
+	return s.Mask
+}
+
+func (s *RedactSetting) replace(text string) string {
+	switch s.Mode {
+	case RedactHash:
+		if len(s.HashKey) == 0 {
+			return s.Mask
+		}
+		mac := hmac.New(sha256.New, s.HashKey)
+		mac.Write([]byte(text))
+		return "hmac-sha256:" + hex.EncodeToString(mac.Sum(nil))
+	case RedactTruncate:
+		keep := s.Keep
+		if keep < 0 {
+			keep = 0
+		}
+		n := utf8.RuneCountInString(text)
+		if n <= keep {
+			// the whole value would be kept
+			return s.Mask
+		}
+		runes := []rune(text)
+		return s.Mask + string(runes[n-keep:])
+	default:
+	}
+	return s.Mask
+}
@@ -0,0 +1,24 @@
+//go:build go1.21
+// +build go1.21
+
+package gomap
+
+import "log/slog"
+
+// LogValue implements slog.LogValuer, the map is logged as a group
+// after it is redacted by the default setting of Redact
+func (m Map) LogValue() slog.Value {
+	return logValue(m.Redact(nil))
+}
+
+func logValue(m Map) slog.Value {
+	attrs := make([]slog.Attr, 0, len(m))
+	for _, k := range m.SortKeys() {
+		if sub, b := m[k].(Map); b {
+			attrs = append(attrs, slog.Attr{Key: k, Value: logValue(sub)})
+			continue
+		}
+		attrs = append(attrs, slog.Any(k, m[k]))
+	}
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,28 @@
+//go:build go1.21
+// +build go1.21
+
+package gomap
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestMap_LogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Info("login", "req", Map{"user": "bob", "password": "hunter2",
+		"auth": Map{"token": "abc", "card": "4111 1111 1111 1111"}})
+	got := buf.String()
+	want := `"req":{"auth":{"card":"******","token":"******"},"password":"******","user":"bob"}`
+	if !strings.Contains(got, want) {
+		t.Errorf("log = %s, want %s", got, want)
+	}
+	for _, secret := range []string{"hunter2", "abc", "4111"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("log = %s, contains %s", got, secret)
+		}
+	}
+}
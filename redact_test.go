@@ -0,0 +1,104 @@
+package gomap
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+// hmacOf returns the RedactHash text of value with key
+func hmacOf(key, value string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(value))
+	return "hmac-sha256:" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func Test_isCardNumber(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{text: "4111111111111111", want: true},
+		{text: "4111 1111 1111 1111", want: true},
+		{text: "4111-1111-1111-1111", want: true},
+		{text: "378282246310005", want: true},
+		{text: "6011111111111117", want: true},
+		{text: "4111111111111112", want: false},
+		{text: "1697712345678", want: false},
+		{text: "411111111111", want: false},
+		{text: "41111111111111111111", want: false},
+		{text: "4111x111111111111", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := isCardNumber(tt.text); got != tt.want {
+				t.Errorf("isCardNumber() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMap_Redact(t *testing.T) {
+	tests := []struct {
+		name     string
+		m        Map
+		patterns []string
+		opts     []RedactOption
+		want     Map
+	}{
+		{name: "keys", m: Map{"Password": "p", "access_token": "t", "user": "u"},
+			want: Map{"Password": "******", "access_token": "******", "user": "u"}},
+		{name: "shape", m: Map{"secret": Map{"a": []interface{}{1, nil}}, "cookie": []Map{{"b": 2}}},
+			want: Map{"secret": Map{"a": []interface{}{"******", nil}}, "cookie": []Map{{"b": "******"}}}},
+		{name: "patterns", m: Map{"user": Map{"email": "e", "name": "n"}, "list": []Map{{"email": "x"}}},
+			patterns: []string{"user.email", "list.email"},
+			want:     Map{"user": Map{"email": "******", "name": "n"}, "list": []Map{{"email": "******"}}}},
+		{name: "card in text", m: Map{"note": "paid by 4111-1111-1111-1111 at 1697712345678"},
+			want: Map{"note": "paid by ****** at 1697712345678"}},
+		{name: "not a card in text", m: Map{"note": "order 4111111111111112"},
+			want: Map{"note": "order 4111111111111112"}},
+		{name: "card integers", m: Map{"a": 4111111111111111, "b": int64(4111111111111111), "c": uint64(4111111111111111),
+			"d": float64(4111111111111111), "e": json.Number("4111111111111111")},
+			want: Map{"a": "******", "b": "******", "c": "******", "d": "******", "e": "******"}},
+		{name: "timestamp integer", m: Map{"ts": int64(1697712345678), "n": 12},
+			want: Map{"ts": int64(1697712345678), "n": 12}},
+		{name: "cards disabled", m: Map{"a": 4111111111111111, "b": "4111111111111111"}, opts: []RedactOption{RedactCards(false)},
+			want: Map{"a": 4111111111111111, "b": "4111111111111111"}},
+		{name: "values", m: Map{"note": "mail a@b.c"}, opts: []RedactOption{RedactValues(regexp.MustCompile(`\S+@\S+`))},
+			want: Map{"note": "mail ******"}},
+		{name: "truncate", m: Map{"token": "abcdefgh", "cookie": "abc"}, opts: []RedactOption{RedactWithMode(RedactTruncate)},
+			want: Map{"token": "******efgh", "cookie": "******"}},
+		{name: "truncate negative keep", m: Map{"token": "abcdefgh"},
+			opts: []RedactOption{RedactWithMode(RedactTruncate), RedactKeep(-1)}, want: Map{"token": "******"}},
+		{name: "truncate negative keep field", m: Map{"token": "abcdefgh"},
+			opts: []RedactOption{RedactWithMode(RedactTruncate), func(op *RedactSetting) { op.Keep = -3 }},
+			want: Map{"token": "******"}},
+		{name: "truncate password", m: Map{"password": "abcdefgh", "auth_token": Map{"db_passwd": "abcdefgh"}},
+			opts: []RedactOption{RedactWithMode(RedactTruncate)},
+			want: Map{"password": "******", "auth_token": Map{"db_passwd": "******"}}},
+		{name: "hash", m: Map{"token": "abc", "password": "abc", "card": 4111111111111111},
+			opts: []RedactOption{RedactWithHash([]byte("k"))},
+			want: Map{"token": hmacOf("k", "abc"), "password": "******", "card": hmacOf("k", "4111111111111111")}},
+		{name: "hash without key", m: Map{"token": "abc"}, opts: []RedactOption{RedactWithMode(RedactHash)},
+			want: Map{"token": "******"}},
+		{name: "mask keys kept by other keys", m: Map{"password": "p", "token": "t"}, opts: []RedactOption{RedactKeys("user")},
+			want: Map{"password": "******", "token": "t"}},
+		{name: "mask text", m: Map{"token": "t"}, opts: []RedactOption{RedactMaskText("[x]")}, want: Map{"token": "[x]"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := copyTree(tt.m)
+			got := tt.m.Redact(tt.patterns, tt.opts...)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Redact() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(tt.m, before) {
+				t.Errorf("Redact() changed the map to %v", tt.m)
+			}
+		})
+	}
+}
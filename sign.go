@@ -0,0 +1,191 @@
+package gomap
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"hash"
+	"net/url"
+	"strings"
+)
+
+// SignType the digest algorithm of signature
+type SignType int
+
+const (
+	// SignMD5 digest the canonical string with MD5
+	SignMD5 SignType = iota
+	// SignHMACSHA256 digest the canonical string with HMAC-SHA256 and the key
+	SignHMACSHA256
+	// SignSHA256 digest the canonical string with SHA256
+	SignSHA256
+)
+
+// SignSetting defines how the canonical string is built and signed
+type SignSetting struct {
+	Type SignType
+	// Field is the key of signature in map, it is excluded from the canonical string, default is "sign"
+	Field string
+	// Exclude are the other keys excluded from the canonical string
+	Exclude []string
+	// KeyName is the name of the appended key, "&key=..." is appended, the key is not appended if it is empty
+	KeyName string
+	// Escape url escape the keys and values, default is true, they are written unescaped if it is false
+	Escape bool
+	// Upper write the signature in upper case hex
+	Upper bool
+}
+
+// SignOption ...
+type SignOption func(op *SignSetting)
+
+func defaultSignSetting() *SignSetting {
+	return &SignSetting{
+		Type:    SignMD5,
+		Field:   "sign",
+		KeyName: "key",
+		Escape:  true,
+		Upper:   true,
+	}
+}
+
+// SignWithType set the digest algorithm
+func SignWithType(t SignType) SignOption {
+	return func(op *SignSetting) {
+		op.Type = t
+	}
+}
+
+// SignField set the key of signature in map
+func SignField(field string) SignOption {
+	return func(op *SignSetting) {
+		op.Field = field
+	}
+}
+
+// SignExclude set the other keys excluded from the canonical string
+func SignExclude(keys ...string) SignOption {
+	return func(op *SignSetting) {
+		op.Exclude = keys
+	}
+}
+
+// SignKeyName set the name of the appended key
+func SignKeyName(name string) SignOption {
+	return func(op *SignSetting) {
+		op.KeyName = name
+	}
+}
+
+// SignEscape enable or disable the url escaping of keys and values
+func SignEscape(b bool) SignOption {
+	return func(op *SignSetting) {
+		op.Escape = b
+	}
+}
+
+// SignUpper enable or disable the upper case signature
+func SignUpper(b bool) SignOption {
+	return func(op *SignSetting) {
+		op.Upper = b
+	}
+}
+
+func newSignSetting(opts ...SignOption) *SignSetting {
+	setting := defaultSignSetting()
+	for i := range opts {
+		opts[i](setting)
+	}
+	return setting
+}
+
+// CanonicalString returns the string to be signed, "k1=v1&k2=v2&key=...",
+// the keys are in sorted order, the empty values and the signature field are excluded.
+// The nested maps and arrays are flattened as EncodeURL does, such as "a.b=1&c=2&c=3",
+// the appended key is written as it is
+func CanonicalString(m Map, key string, opts ...SignOption) string {
+	return newSignSetting(opts...).canonical(m, key)
+}
+
+// Sign returns the signature of m with key
+func Sign(m Map, key string, opts ...SignOption) string {
+	setting := newSignSetting(opts...)
+	return setting.sign(setting.canonical(m, key), key)
+}
+
+// Verify check the signature in the field of m, the map parsed from XML by ParseXMLWith
+// should keep the text values by XMLCast(false), so they are signed as received
+func Verify(m Map, key string, opts ...SignOption) bool {
+	setting := newSignSetting(opts...)
+	got := formatScalar(m[setting.Field])
+	if got == "" {
+		return false
+	}
+	want := setting.sign(setting.canonical(m, key), key)
+	return subtle.ConstantTimeCompare([]byte(strings.ToLower(got)), []byte(strings.ToLower(want))) == 1
+}
+
+func (s *SignSetting) excluded(k string) bool {
+	if k == s.Field {
+		return true
+	}
+	for i := range s.Exclude {
+		if s.Exclude[i] == k {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *SignSetting) canonical(m Map, key string) string {
+	var buf strings.Builder
+	flat := &URLSetting{}
+	for _, k := range m.SortKeys() {
+		if s.excluded(k) {
+			continue
+		}
+		flat.encode(k, m[k], func(key string, v string) {
+			if v == "" {
+				return
+			}
+			if s.Escape {
+				key, v = url.QueryEscape(key), url.QueryEscape(v)
+			}
+			if buf.Len() > 0 {
+				buf.WriteByte('&')
+			}
+			buf.WriteString(key)
+			buf.WriteByte('=')
+			buf.WriteString(v)
+		})
+	}
+	if s.KeyName != "" {
+		if buf.Len() > 0 {
+			buf.WriteByte('&')
+		}
+		buf.WriteString(s.KeyName)
+		buf.WriteByte('=')
+		buf.WriteString(key)
+	}
+	return buf.String()
+}
+
+func (s *SignSetting) sign(canonical string, key string) string {
+	var h hash.Hash
+	switch s.Type {
+	case SignHMACSHA256:
+		h = hmac.New(sha256.New, []byte(key))
+	case SignSHA256:
+		h = sha256.New()
+	default:
+		h = md5.New()
+	}
+	h.Write([]byte(canonical))
+	sum := hex.EncodeToString(h.Sum(nil))
+	if s.Upper {
+		return strings.ToUpper(sum)
+	}
+	return sum
+}
@@ -0,0 +1,48 @@
+package gomap
+
+import "testing"
+
+func TestCanonicalString(t *testing.T) {
+	tests := []struct {
+		name string
+		m    Map
+		opts []SignOption
+		want string
+	}{
+		{name: "sorted and excluded", m: Map{"b": "2", "a": "1", "sign": "x", "e": ""}, want: "a=1&b=2&key=k"},
+		{name: "escaped by default", m: Map{"a b": "x&y=z"}, want: "a+b=x%26y%3Dz&key=k"},
+		{name: "unescaped", m: Map{"a b": "x&y"}, opts: []SignOption{SignEscape(false)}, want: "a b=x&y&key=k"},
+		{name: "nested", m: Map{"a": Map{"c": 1, "b": ""}, "d": []interface{}{2, 3}}, want: "a.c=1&d=2&d=3&key=k"},
+		{name: "nested maps in array", m: Map{"a": []Map{{"x": 1}}}, want: "a.0.x=1&key=k"},
+		{name: "excluded keys", m: Map{"a": "1", "b": "2"}, opts: []SignOption{SignExclude("b"), SignKeyName("")}, want: "a=1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanonicalString(tt.m, "k", tt.opts...); got != tt.want {
+				t.Errorf("CanonicalString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSign(t *testing.T) {
+	// the example of WeChat Pay
+	m := Map{"appid": "wxd930ea5d5a258f4f", "mch_id": "10000100", "device_info": "1000",
+		"body": "test", "nonce_str": "ibuaiVcKdpRxkhJA"}
+	key := "192006250b4c09247ec02edce69f6a2d"
+	want := "9A0A8659F005D6984697E2CA0A9CF3B7"
+	if got := Sign(m, key); got != want {
+		t.Fatalf("Sign() = %v, want %v", got, want)
+	}
+	m["sign"] = want
+	if !Verify(m, key) {
+		t.Errorf("Verify() = false, want true")
+	}
+	if Verify(m, "other") {
+		t.Errorf("Verify() with other key = true, want false")
+	}
+	m["sign"] = ""
+	if Verify(m, key) {
+		t.Errorf("Verify() without sign = true, want false")
+	}
+}
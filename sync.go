@@ -0,0 +1,329 @@
+package gomap
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+)
+
+// SyncMap is a Map which is safe for concurrent use,
+// every path operation is done atomically under a read/write lock,
+// including the creation of the intermediate maps of Set.
+// The maps and slices are deep copied when they are written and read,
+// so the caller can not change the stored values without the lock
+type SyncMap struct {
+	mu sync.RWMutex
+	m  Map
+}
+
+// NewSyncMap create a SyncMap with a deep copy of the values of maps,
+// so the maps can not change it without the lock
+func NewSyncMap(maps ...Map) *SyncMap {
+	return &SyncMap{m: copyTree(Merge(maps...)).(Map)}
+}
+
+// Set set value to the dotted key
+func (s *SyncMap) Set(key string, v interface{}) *SyncMap {
+	s.mu.Lock()
+	s.m.Set(key, copyTree(v))
+	s.mu.Unlock()
+	return s
+}
+
+// SetPath set value to the path of keys
+func (s *SyncMap) SetPath(keys []string, v interface{}) *SyncMap {
+	s.mu.Lock()
+	s.m.SetPath(keys, copyTree(v))
+	s.mu.Unlock()
+	return s
+}
+
+//SetNil set value, if the key is not exist
+func (s *SyncMap) SetNil(key string, v interface{}) *SyncMap {
+	s.mu.Lock()
+	s.m.SetNil(key, copyTree(v))
+	s.mu.Unlock()
+	return s
+}
+
+//Replace replace will set value, if the key is exist
+func (s *SyncMap) Replace(key string, v interface{}) *SyncMap {
+	s.mu.Lock()
+	s.m.Replace(key, copyTree(v))
+	s.mu.Unlock()
+	return s
+}
+
+// Update set the value of key to the result of fn with a copy of the old value,
+// it returns a copy of the new value. fn is called under the lock, so it must not call the methods of s
+func (s *SyncMap) Update(key string, fn func(old interface{}) interface{}) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v := copyTree(fn(copyTree(s.m.Get(key))))
+	s.m.Set(key, v)
+	return copyTree(v)
+}
+
+// CompareAndSwap set the value of key to new if the current value is equal to old by reflect.DeepEqual,
+// a nil old matches the key which is not exist
+func (s *SyncMap) CompareAndSwap(key string, old, new interface{}) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !reflect.DeepEqual(s.m.Get(key), old) {
+		return false
+	}
+	s.m.Set(key, copyTree(new))
+	return true
+}
+
+// Get get interface from map without default,
+// the nested maps and slices are returned as deep copies
+func (s *SyncMap) Get(key string) interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return copyTree(s.m.Get(key))
+}
+
+// GetD get interface from map with default
+func (s *SyncMap) GetD(key string, d interface{}) interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if v := s.m.GetD(key, nil); v != nil {
+		return copyTree(v)
+	}
+	return d
+}
+
+// GetPath returns a deep copy of the element in the tree indicated by keys
+func (s *SyncMap) GetPath(keys []string) interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(keys) == 0 {
+		return copyTree(s.m)
+	}
+	return copyTree(s.m.GetPath(keys))
+}
+
+// GetMap get a deep copy of the map of key
+func (s *SyncMap) GetMap(key string) Map {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if v := s.m.GetMap(key); v != nil {
+		return copyTree(v).(Map)
+	}
+	return nil
+}
+
+// GetMapArray get a deep copy of the map array of key without default
+func (s *SyncMap) GetMapArray(key string) []Map {
+	return s.GetMapArrayD(key, nil)
+}
+
+// GetMapArrayD get a deep copy of the map array of key with default
+func (s *SyncMap) GetMapArrayD(key string, d []Map) []Map {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if v := s.m.GetMapArrayD(key, nil); v != nil {
+		return copyTree(v).([]Map)
+	}
+	return d
+}
+
+// GetArray get a deep copy of the array of key without default
+func (s *SyncMap) GetArray(key string) []interface{} {
+	return s.GetArrayD(key, nil)
+}
+
+// GetArrayD get a deep copy of the array of key with default
+func (s *SyncMap) GetArrayD(key string, d []interface{}) []interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if v := s.m.GetArrayD(key, nil); v != nil {
+		return copyTree(v).([]interface{})
+	}
+	return d
+}
+
+// GetStringArray get a copy of the string array of key without default
+func (s *SyncMap) GetStringArray(key string) []string {
+	return s.GetStringArrayD(key, []string{})
+}
+
+// GetStringArrayD get a copy of the string array of key with default
+func (s *SyncMap) GetStringArrayD(key string, d []string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if v := s.m.GetStringArrayD(key, nil); v != nil {
+		return append([]string{}, v...)
+	}
+	return d
+}
+
+// GetBytes get a copy of the bytes of key without default
+func (s *SyncMap) GetBytes(key string) []byte {
+	return s.GetBytesD(key, nil)
+}
+
+// GetBytesD get a copy of the bytes of key with default
+func (s *SyncMap) GetBytesD(key string, d []byte) []byte {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if v := s.m.GetBytesD(key, nil); v != nil {
+		return append([]byte{}, v...)
+	}
+	return d
+}
+
+// GetString get string from map without default
+func (s *SyncMap) GetString(key string) string {
+	return s.GetStringD(key, "")
+}
+
+// GetStringD get string from map with default
+func (s *SyncMap) GetStringD(key string, d string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.GetStringD(key, d)
+}
+
+// GetBool get bool from map without default
+func (s *SyncMap) GetBool(key string) bool {
+	return s.GetBoolD(key, false)
+}
+
+// GetBoolD get bool from map with default
+func (s *SyncMap) GetBoolD(key string, d bool) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.GetBoolD(key, d)
+}
+
+// GetInt64 get int64 from map without default
+func (s *SyncMap) GetInt64(key string) (int64, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.GetInt64(key)
+}
+
+// GetInt64D get int64 from map with default
+func (s *SyncMap) GetInt64D(key string, d int64) int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.GetInt64D(key, d)
+}
+
+// GetNumber get float64 from map without default
+func (s *SyncMap) GetNumber(key string) (float64, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.GetNumber(key)
+}
+
+// GetNumberD get float64 from map with default
+func (s *SyncMap) GetNumberD(key string, d float64) float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.GetNumberD(key, d)
+}
+
+//Has check if key exist
+func (s *SyncMap) Has(key string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Has(key)
+}
+
+//Check check all input keys, see Map.Check
+func (s *SyncMap) Check(keys ...string) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Check(keys...)
+}
+
+// HasPath returns true if the given path of keys exists
+func (s *SyncMap) HasPath(keys []string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.HasPath(keys)
+}
+
+// Delete delete key value if key is exist
+func (s *SyncMap) Delete(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.m.Delete(key)
+}
+
+// DeletePath delete keys value if keys is exist
+func (s *SyncMap) DeletePath(keys []string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.m.DeletePath(keys)
+}
+
+// Append append source map, see Map.Append
+func (s *SyncMap) Append(p Map) *SyncMap {
+	s.mu.Lock()
+	s.m.Append(copyTree(p).(Map))
+	s.mu.Unlock()
+	return s
+}
+
+// AppendArray append the values to the array of key
+func (s *SyncMap) AppendArray(key string, v ...interface{}) *SyncMap {
+	s.mu.Lock()
+	s.m.AppendArray(key, copyTree(v).([]interface{})...)
+	s.mu.Unlock()
+	return s
+}
+
+//ReplaceJoin insert map p with replace
+func (s *SyncMap) ReplaceJoin(p Map) *SyncMap {
+	s.mu.Lock()
+	s.m.ReplaceJoin(copyTree(p).(Map))
+	s.mu.Unlock()
+	return s
+}
+
+//Join insert map p without replace
+func (s *SyncMap) Join(p Map) *SyncMap {
+	s.mu.Lock()
+	s.m.Join(copyTree(p).(Map))
+	s.mu.Unlock()
+	return s
+}
+
+// Len returns the number of the top level keys
+func (s *SyncMap) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.m)
+}
+
+// Range range a copy of the top level keys, so f can call the methods of s
+func (s *SyncMap) Range(f func(key string, value interface{}) bool) {
+	s.Clone().Range(f)
+}
+
+//Clone deep copy the map
+func (s *SyncMap) Clone() Map {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return copyTree(s.m).(Map)
+}
+
+//String transfer map to JSON string
+func (s *SyncMap) String() string {
+	v, err := s.MarshalJSON()
+	if err != nil {
+		return ""
+	}
+	return string(v)
+}
+
+// MarshalJSON implements json.Marshaler
+func (s *SyncMap) MarshalJSON() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return json.Marshal(s.m)
+}
@@ -0,0 +1,151 @@
+package gomap
+
+import (
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestSyncMap_Copy(t *testing.T) {
+	tests := []struct {
+		name   string
+		write  func(s *SyncMap, v Map)
+		read   func(s *SyncMap) Map
+		mutate func(got Map)
+	}{
+		{
+			name:   "NewSyncMap",
+			read:   func(s *SyncMap) Map { return s.GetMap("v") },
+			mutate: func(got Map) { got["b"] = "changed" },
+		},
+		{
+			name:  "Set and Get",
+			write: func(s *SyncMap, v Map) { s.Set("v", v) },
+			read:  func(s *SyncMap) Map { return s.Get("v").(Map) },
+		},
+		{
+			name:  "SetPath and GetPath",
+			write: func(s *SyncMap, v Map) { s.SetPath([]string{"v"}, v) },
+			read:  func(s *SyncMap) Map { return s.GetPath([]string{"v"}).(Map) },
+		},
+		{
+			name:  "Update",
+			write: func(s *SyncMap, v Map) { s.Update("v", func(interface{}) interface{} { return v }) },
+			read: func(s *SyncMap) Map {
+				return s.Update("v", func(old interface{}) interface{} { return old }).(Map)
+			},
+		},
+		{
+			name:  "CompareAndSwap",
+			write: func(s *SyncMap, v Map) { s.CompareAndSwap("v", s.Get("v"), v) },
+			read:  func(s *SyncMap) Map { return s.Clone()["v"].(Map) },
+		},
+		{
+			name:  "ReplaceJoin",
+			write: func(s *SyncMap, v Map) { s.ReplaceJoin(Map{"v": v}) },
+			read:  func(s *SyncMap) Map { return s.GetPath(nil).(Map)["v"].(Map) },
+		},
+		{
+			name:  "GetArray",
+			write: func(s *SyncMap, v Map) { s.Set("v", v).Set("l", []interface{}{v}) },
+			read:  func(s *SyncMap) Map { return s.GetArray("l")[0].(Map) },
+		},
+		{
+			name:  "GetMapArray",
+			write: func(s *SyncMap, v Map) { s.Set("v", v).Set("l", []Map{v}) },
+			read:  func(s *SyncMap) Map { return s.GetMapArray("l")[0] },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := Map{"a": Map{"b": 1}, "b": "x"}
+			s := NewSyncMap(Map{"v": v})
+			if tt.write != nil {
+				s = NewSyncMap()
+				tt.write(s, v)
+			}
+			// change the written value and the read value, the stored value is not changed
+			v["a"].(Map)["b"] = 2
+			got := tt.read(s)
+			got["a"].(Map)["b"] = 3
+			if tt.mutate != nil {
+				tt.mutate(got)
+			}
+			want := Map{"a": Map{"b": 1}, "b": "x"}
+			if got := tt.read(s); !reflect.DeepEqual(got, want) {
+				t.Errorf("stored value = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestSyncMap_Accessors(t *testing.T) {
+	s := NewSyncMap(Map{"s": []string{"a"}, "b": []byte("x")})
+	s.GetStringArray("s")[0] = "changed"
+	s.GetBytes("b")[0] = 'y'
+	if got := s.GetStringArray("s"); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("GetStringArray() = %v, want [a]", got)
+	}
+	if got := s.GetBytes("b"); string(got) != "x" {
+		t.Errorf("GetBytes() = %s, want x", got)
+	}
+	s.AppendArray("l", 1).AppendArray("l", 2)
+	if got := s.GetArray("l"); !reflect.DeepEqual(got, []interface{}{1, 2}) {
+		t.Errorf("GetArray() = %v, want [1 2]", got)
+	}
+	if got := s.Check("s", "b", "x"); got != 2 {
+		t.Errorf("Check() = %v, want 2", got)
+	}
+	if got := s.GetMapArrayD("x", []Map{}); got == nil || len(got) != 0 {
+		t.Errorf("GetMapArrayD() = %v, want []", got)
+	}
+}
+
+// run with -race, the caller changes its maps while the other goroutines read the SyncMap
+func TestSyncMap_Concurrent(t *testing.T) {
+	s := NewSyncMap()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				v := Map{"n": j, "l": []interface{}{Map{"i": i}}}
+				s.Set("m."+strconv.Itoa(i), v)
+				v["n"] = -1
+				v["l"].([]interface{})[0].(Map)["i"] = -1
+				s.Update("count", func(old interface{}) interface{} {
+					n, _ := old.(int)
+					return n + 1
+				})
+				got := s.Update("list", func(old interface{}) interface{} {
+					arr, _ := old.([]interface{})
+					if len(arr) > 8 {
+						arr = arr[1:]
+					}
+					return append(arr, Map{"i": i})
+				}).([]interface{})
+				got[len(got)-1].(Map)["i"] = -1
+				if m := s.GetMap("m"); m != nil {
+					m[strconv.Itoa(i)] = nil
+				}
+				_ = s.String()
+			}
+		}(i)
+	}
+	wg.Wait()
+	if got := s.Get("count"); got != 8*100 {
+		t.Errorf("count = %v, want %v", got, 8*100)
+	}
+	for i := 0; i < 8; i++ {
+		if got := s.GetMap("m." + strconv.Itoa(i)); got["n"] != 99 {
+			t.Errorf("m.%d = %v, want n 99", i, got)
+		}
+	}
+	for _, v := range s.GetArray("list") {
+		if v.(Map)["i"] == -1 {
+			t.Fatalf("list is changed by the returned value of Update")
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package gomap
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+)
+
+//ToTOML transfer map to TOML, the keys are written in sorted order
+func (m Map) ToTOML() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(m); err != nil {
+		return nil, fmt.Errorf("map to toml error:%w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+//ParseTOML parse TOML bytes to map, the tables are converted to Map,
+// the arrays of tables to []Map and the datetime values to time.Time
+func (m Map) ParseTOML(b []byte) error {
+	var v map[string]interface{}
+	if err := toml.Unmarshal(b, &v); err != nil {
+		return fmt.Errorf("toml to map error:%w", err)
+	}
+	for k := range v {
+		m[k] = convertTOML(v[k])
+	}
+	return nil
+}
+
+func convertTOML(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		m := make(Map, len(v))
+		for k := range v {
+			m[k] = convertTOML(v[k])
+		}
+		return m
+	case []map[string]interface{}:
+		maps := make([]Map, len(v))
+		for i := range v {
+			maps[i] = convertTOML(v[i]).(Map)
+		}
+		return maps
+	case []interface{}:
+		for i := range v {
+			v[i] = convertTOML(v[i])
+		}
+		return v
+	default:
+	}
+	return value
+}
@@ -0,0 +1,149 @@
+package gomap
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+//TransformKeys returns a copy of map with the keys rewritten by fn,
+// it recurses through Map, map[string]interface{}, []Map and []interface{},
+// the later key wins if two keys are rewritten to the same key
+func (m Map) TransformKeys(fn func(key string) string) Map {
+	return transformKeys(m, fn).(Map)
+}
+
+func transformKeys(value interface{}, fn func(string) string) interface{} {
+	switch v := value.(type) {
+	case Map:
+		if v == nil {
+			return v
+		}
+		m := make(Map, len(v))
+		for _, k := range v.SortKeys() {
+			m[fn(k)] = transformKeys(v[k], fn)
+		}
+		return m
+	case map[string]interface{}:
+		return transformKeys(Map(v), fn)
+	case []Map:
+		arr := make([]Map, len(v))
+		for i := range v {
+			arr[i] = transformKeys(v[i], fn).(Map)
+		}
+		return arr
+	case []interface{}:
+		arr := make([]interface{}, len(v))
+		for i := range v {
+			arr[i] = transformKeys(v[i], fn)
+		}
+		return arr
+	default:
+	}
+	return value
+}
+
+// splitWords split key to the words by the separators "_", "-", " "
+// and the case changes, "userID" and "HTTPServer" are "user" "ID" and "HTTP" "Server"
+func splitWords(key string) []string {
+	var words []string
+	runes := []rune(key)
+	start := 0
+	flush := func(end int) {
+		if end > start {
+			words = append(words, string(runes[start:end]))
+		}
+		start = end
+	}
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || r == ' ':
+			flush(i)
+			start = i + 1
+		case i > start && unicode.IsUpper(r):
+			prev := runes[i-1]
+			if !unicode.IsUpper(prev) || i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+				flush(i)
+			}
+		default:
+		}
+	}
+	flush(len(runes))
+	return words
+}
+
+func joinWords(key string, sep string, fn func(i int, word string) string) string {
+	words := splitWords(key)
+	for i := range words {
+		words[i] = fn(i, words[i])
+	}
+	return strings.Join(words, sep)
+}
+
+func title(word string) string {
+	runes := []rune(strings.ToLower(word))
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// SnakeCase transfer key to snake_case, "userID" is "user_id"
+func SnakeCase(key string) string {
+	return joinWords(key, "_", func(_ int, word string) string {
+		return strings.ToLower(word)
+	})
+}
+
+// KebabCase transfer key to kebab-case, "userID" is "user-id"
+func KebabCase(key string) string {
+	return joinWords(key, "-", func(_ int, word string) string {
+		return strings.ToLower(word)
+	})
+}
+
+// CamelCase transfer key to camelCase, "user_id" is "userId"
+func CamelCase(key string) string {
+	return joinWords(key, "", func(i int, word string) string {
+		if i == 0 {
+			return strings.ToLower(word)
+		}
+		return title(word)
+	})
+}
+
+// PascalCase transfer key to PascalCase, "user_id" is "UserId"
+func PascalCase(key string) string {
+	return joinWords(key, "", func(_ int, word string) string {
+		return title(word)
+	})
+}
+
+// Lower transfer key to lower case
+func Lower(key string) string {
+	return strings.ToLower(key)
+}
+
+//Rename move the values from the old dotted paths to the new dotted paths,
+// all the values are taken before they are set, so the paths can be swapped,
+// the paths which are not exist are ignored
+func (m Map) Rename(paths map[string]string) Map {
+	olds := make([]string, 0, len(paths))
+	for k := range paths {
+		olds = append(olds, k)
+	}
+	sort.Strings(olds)
+	values := make(map[string]interface{}, len(olds))
+	for _, old := range olds {
+		keys := strings.Split(old, ".")
+		if !m.HasPath(keys) {
+			continue
+		}
+		values[old] = m.GetPath(keys)
+		m.DeletePath(keys)
+	}
+	for _, old := range olds {
+		if v, b := values[old]; b {
+			m.Set(paths[old], v)
+		}
+	}
+	return m
+}
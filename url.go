@@ -0,0 +1,342 @@
+package gomap
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// URLNesting the key format of nested maps
+type URLNesting int
+
+const (
+	// URLDot write the nested key as "a.b"
+	URLDot URLNesting = iota
+	// URLBracket write the nested key as "a[b]"
+	URLBracket
+)
+
+// URLArray the key format of array elements
+type URLArray int
+
+const (
+	// URLArrayRepeat repeat the key for every element, "a=1&a=2"
+	URLArrayRepeat URLArray = iota
+	// URLArrayBrackets append "[]" to the key, "a[]=1&a[]=2"
+	URLArrayBrackets
+	// URLArrayIndex append the index to the key, "a[0]=1" or "a.0=1"
+	URLArrayIndex
+)
+
+// URLSetting defines how the nested maps and arrays are encoded
+type URLSetting struct {
+	Nesting URLNesting
+	// Array is the format of scalar arrays, the arrays of maps or arrays are always indexed
+	Array URLArray
+}
+
+// URLOption ...
+type URLOption func(op *URLSetting)
+
+// URLNestingStyle set the key format of nested maps
+func URLNestingStyle(n URLNesting) URLOption {
+	return func(op *URLSetting) {
+		op.Nesting = n
+	}
+}
+
+// URLArrayStyle set the key format of array elements
+func URLArrayStyle(a URLArray) URLOption {
+	return func(op *URLSetting) {
+		op.Array = a
+	}
+}
+
+func newURLSetting(opts ...URLOption) *URLSetting {
+	setting := &URLSetting{}
+	for i := range opts {
+		opts[i](setting)
+	}
+	return setting
+}
+
+func (s *URLSetting) child(prefix string, k string) string {
+	if prefix == "" {
+		return k
+	}
+	if s.Nesting == URLBracket {
+		return prefix + "[" + k + "]"
+	}
+	return prefix + "." + k
+}
+
+// EncodeURL transfer map to url encode with all scalar values and nested maps and arrays,
+// the keys are written in sorted order
+func (m Map) EncodeURL(opts ...URLOption) string {
+	var buf strings.Builder
+	newURLSetting(opts...).encode("", m, func(key string, v string) {
+		writeURLPair(&buf, key, v)
+	})
+	return buf.String()
+}
+
+// ToValues transfer map to url.Values with the same keys as EncodeURL
+func (m Map) ToValues(opts ...URLOption) url.Values {
+	values, _ := url.ParseQuery(m.EncodeURL(opts...))
+	return values
+}
+
+func writeURLPair(buf *strings.Builder, key string, v string) {
+	if buf.Len() > 0 {
+		buf.WriteByte('&')
+	}
+	buf.WriteString(url.QueryEscape(key))
+	buf.WriteByte('=')
+	buf.WriteString(url.QueryEscape(v))
+}
+
+// encode calls emit with the unescaped key and text of every scalar value in sorted order
+func (s *URLSetting) encode(key string, value interface{}, emit func(key string, v string)) {
+	if sub, b := toMap(value); b {
+		for _, k := range sub.SortKeys() {
+			s.encode(s.child(key, k), sub[k], emit)
+		}
+		return
+	}
+	arr, b := toArray(value)
+	if !b {
+		emit(key, formatScalar(value))
+		return
+	}
+	indexed := s.Array == URLArrayIndex
+	for i := range arr {
+		if _, b := toMap(arr[i]); b {
+			indexed = true
+		} else if _, b := toArray(arr[i]); b {
+			indexed = true
+		}
+	}
+	for i := range arr {
+		switch {
+		case indexed:
+			s.encode(s.child(key, strconv.Itoa(i)), arr[i], emit)
+		case s.Array == URLArrayBrackets:
+			emit(key+"[]", formatScalar(arr[i]))
+		default:
+			emit(key, formatScalar(arr[i]))
+		}
+	}
+}
+
+// toArray returns the value as []interface{} if it is a slice, []byte is not a slice here
+func toArray(value interface{}) ([]interface{}, bool) {
+	switch v := value.(type) {
+	case []interface{}:
+		return v, true
+	case []byte:
+		return nil, false
+	case []Map:
+		arr := make([]interface{}, len(v))
+		for i := range v {
+			arr[i] = v[i]
+		}
+		return arr, true
+	case nil:
+		return nil, false
+	default:
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+	arr, err := reflectValue(rv)
+	if err != nil {
+		return nil, false
+	}
+	return arr.([]interface{}), true
+}
+
+// formatScalar formats the scalar value as text
+func formatScalar(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		return v.String()
+	default:
+	}
+	return fmt.Sprint(value)
+}
+
+// ParseEncodedURL parse the url encode to map, the keys can be "a.b", "a[b]", "a[]" and "a[0]",
+// the repeated keys are set as array, the values are kept as string.
+// It returns ErrPathConflict for the keys like "a" and "a.b"
+func ParseEncodedURL(s string) (Map, error) {
+	m := New()
+	for _, pair := range strings.Split(s, "&") {
+		if pair == "" {
+			continue
+		}
+		k, v := pair, ""
+		if i := strings.IndexByte(pair, '='); i >= 0 {
+			k, v = pair[:i], pair[i+1:]
+		}
+		key, err := url.QueryUnescape(k)
+		if err != nil {
+			return nil, fmt.Errorf("url to map error:%w", err)
+		}
+		value, err := url.QueryUnescape(v)
+		if err != nil {
+			return nil, fmt.Errorf("url to map error:%w", err)
+		}
+		if err := insertURLValue(m, parseURLKey(key), value); err != nil {
+			return nil, fmt.Errorf("url to map error:%w", err)
+		}
+	}
+	compactArrays(m)
+	return m, nil
+}
+
+// FromValues transfer url.Values to map with the same keys as ParseEncodedURL
+func FromValues(values url.Values) (Map, error) {
+	m := New()
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		path := parseURLKey(k)
+		for _, v := range values[k] {
+			if err := insertURLValue(m, path, v); err != nil {
+				return nil, fmt.Errorf("url to map error:%w", err)
+			}
+		}
+	}
+	compactArrays(m)
+	return m, nil
+}
+
+// parseURLKey splits the key "a.b[c][]" to "a", "b", "c", ""
+func parseURLKey(key string) []string {
+	var path []string
+	i := strings.IndexByte(key, '[')
+	if i < 0 {
+		return strings.Split(key, ".")
+	}
+	path = strings.Split(key[:i], ".")
+	rest := key[i:]
+	for len(rest) > 0 {
+		switch rest[0] {
+		case '[':
+			j := strings.IndexByte(rest, ']')
+			if j < 0 {
+				return append(path, rest[1:])
+			}
+			path = append(path, rest[1:j])
+			rest = rest[j+1:]
+		case '.':
+			j := strings.IndexAny(rest[1:], ".[")
+			if j < 0 {
+				return append(path, rest[1:])
+			}
+			path = append(path, rest[1:j+1])
+			rest = rest[j+1:]
+		default:
+			j := strings.IndexAny(rest, ".[")
+			if j < 0 {
+				return append(path, rest)
+			}
+			path = append(path, rest[:j])
+			rest = rest[j:]
+		}
+	}
+	return path
+}
+
+// insertURLValue sets v to path of m, the array elements are set with their index as key,
+// then compactArrays converts them to arrays. It returns ErrPathConflict if a value and a map are
+// set to the same key
+func insertURLValue(m Map, path []string, v string) error {
+	node := m
+	for i, k := range path {
+		if k == "" {
+			k = strconv.Itoa(len(node))
+		}
+		if i == len(path)-1 {
+			switch old := node[k].(type) {
+			case nil:
+				node[k] = v
+			case string:
+				node[k] = []interface{}{old, v}
+			case []interface{}:
+				node[k] = append(old, v)
+			default:
+				return fmt.Errorf("%w: %s", ErrPathConflict, strings.Join(path[:i+1], "."))
+			}
+			return nil
+		}
+		switch sub := node[k].(type) {
+		case nil:
+			node[k] = New()
+			node = node[k].(Map)
+		case Map:
+			node = sub
+		default:
+			return fmt.Errorf("%w: %s", ErrPathConflict, strings.Join(path[:i+1], "."))
+		}
+	}
+	return nil
+}
+
+// compactArrays converts the nested maps whose keys are all indexes to arrays
+func compactArrays(m Map) {
+	for k, v := range m {
+		if sub, b := v.(Map); b {
+			compactArrays(sub)
+			if arr, b := indexedArray(sub); b {
+				m[k] = toMapArray(arr)
+			}
+		}
+	}
+}
+
+func indexedArray(m Map) ([]interface{}, bool) {
+	if len(m) == 0 {
+		return nil, false
+	}
+	keys := make([]string, 0, len(m))
+	indexes := make(map[string]int, len(m))
+	for k := range m {
+		i, err := strconv.Atoi(k)
+		if err != nil || i < 0 {
+			return nil, false
+		}
+		keys = append(keys, k)
+		indexes[k] = i
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return indexes[keys[i]] < indexes[keys[j]]
+	})
+	arr := make([]interface{}, len(keys))
+	for i, k := range keys {
+		arr[i] = m[k]
+	}
+	return arr, true
+}
@@ -0,0 +1,58 @@
+package gomap
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestParseEncodedURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    Map
+		wantErr error
+	}{
+		{name: "dotted", s: "a.b=1&c=2", want: Map{"a": Map{"b": "1"}, "c": "2"}},
+		{name: "brackets", s: "a[b]=1&a[c]=2", want: Map{"a": Map{"b": "1", "c": "2"}}},
+		{name: "repeated", s: "a=1&a=2", want: Map{"a": []interface{}{"1", "2"}}},
+		{name: "appended", s: "a[]=1&a[]=2", want: Map{"a": []interface{}{"1", "2"}}},
+		{name: "indexed maps", s: "a[0][x]=1&a[1][x]=2", want: Map{"a": []Map{{"x": "1"}, {"x": "2"}}}},
+		{name: "escaped", s: "a%5Bb%5D=x+y", want: Map{"a": Map{"b": "x y"}}},
+		{name: "value then map", s: "a=1&a.b=2", wantErr: ErrPathConflict},
+		{name: "map then value", s: "a.b=2&a=1", wantErr: ErrPathConflict},
+		{name: "value then array", s: "a=1&a[]=2", wantErr: ErrPathConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseEncodedURL(tt.s)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseEncodedURL() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseEncodedURL() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseEncodedURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromValues(t *testing.T) {
+	got, err := FromValues(url.Values{"a[b]": {"1"}, "c": {"2", "3"}})
+	if err != nil {
+		t.Fatalf("FromValues() error = %v", err)
+	}
+	want := Map{"a": Map{"b": "1"}, "c": []interface{}{"2", "3"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FromValues() = %v, want %v", got, want)
+	}
+	if _, err := FromValues(url.Values{"a": {"1"}, "a.b": {"2"}}); !errors.Is(err, ErrPathConflict) {
+		t.Errorf("FromValues() error = %v, want %v", err, ErrPathConflict)
+	}
+}
@@ -0,0 +1,137 @@
+package extmap
+
+import (
+	"sort"
+	"strconv"
+)
+
+// isLeaf returns true if value is not a map or an array, or it is an empty map or array
+func isLeaf(value any) bool {
+	switch v := value.(type) {
+	case *Map:
+		return v == nil || len(v.m) == 0
+	case map[string]any:
+		return len(v) == 0
+	case []*Map:
+		return len(v) == 0
+	case []any:
+		return len(v) == 0
+	default:
+	}
+	return true
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func appendPath(path []string, k string) []string {
+	return append(path[:len(path):len(path)], k)
+}
+
+//MapValues returns a copy of map with the leaves rewritten by fn, the leaf is dropped if keep is false.
+// The leaves are the values which are not map or array and the empty maps and arrays,
+// it recurses through *Map, map[string]any, []*Map and []any in sorted order
+func (m *Map) MapValues(fn func(path []string, v any) (value any, keep bool)) *Map {
+	return m.mapValues(nil, fn, false)
+}
+
+//Filter returns a copy of map with the leaves which pred returns true,
+// the maps and arrays which become empty are removed, use it to strip the nil values before ToXML:
+//  m.Filter(func(_ []string, v any) bool { return v != nil })
+func (m *Map) Filter(pred func(path []string, v any) bool) *Map {
+	return m.mapValues(nil, func(path []string, v any) (any, bool) {
+		return v, pred(path, v)
+	}, true)
+}
+
+//Reduce call fn for every leaf in sorted order with the result of the previous call,
+// it returns the last result or init if there is no leaf
+func (m *Map) Reduce(init any, fn func(acc any, path []string, v any) any) any {
+	acc := init
+	reduceValue(nil, m, func(path []string, v any) {
+		acc = fn(acc, path, v)
+	})
+	return acc
+}
+
+func reduceValue(path []string, value any, fn func([]string, any)) {
+	if path != nil && isLeaf(value) {
+		fn(path, value)
+		return
+	}
+	switch v := value.(type) {
+	case *Map:
+		for _, k := range sortedKeys(v.m) {
+			reduceValue(appendPath(path, k), v.m[k], fn)
+		}
+	case map[string]any:
+		for _, k := range sortedKeys(v) {
+			reduceValue(appendPath(path, k), v[k], fn)
+		}
+	case []*Map:
+		for i := range v {
+			reduceValue(appendPath(path, strconv.Itoa(i)), v[i], fn)
+		}
+	case []any:
+		for i := range v {
+			reduceValue(appendPath(path, strconv.Itoa(i)), v[i], fn)
+		}
+	default:
+	}
+}
+
+func (m *Map) mapValues(path []string, fn func([]string, any) (any, bool), prune bool) *Map {
+	n := newWithSetting(m.setting)
+	for _, k := range sortedKeys(m.m) {
+		if v, keep := mapValues(appendPath(path, k), m.m[k], fn, prune); keep {
+			n.m[k] = v
+		}
+	}
+	return n
+}
+
+// mapValues rewrite the leaves of value, the maps and arrays which become empty are dropped if prune is true
+func mapValues(path []string, value any, fn func([]string, any) (any, bool), prune bool) (any, bool) {
+	if isLeaf(value) {
+		return fn(path, value)
+	}
+	switch v := value.(type) {
+	case *Map:
+		n := v.mapValues(path, fn, prune)
+		return n, !prune || len(n.m) > 0
+	case map[string]any:
+		n := make(map[string]any, len(v))
+		for _, k := range sortedKeys(v) {
+			if sub, keep := mapValues(appendPath(path, k), v[k], fn, prune); keep {
+				n[k] = sub
+			}
+		}
+		return n, !prune || len(n) > 0
+	case []*Map:
+		arr := make([]*Map, 0, len(v))
+		for i := range v {
+			if sub, keep := mapValues(appendPath(path, strconv.Itoa(i)), v[i], fn, prune); keep {
+				if sm, b := sub.(*Map); b {
+					arr = append(arr, sm)
+				}
+			}
+		}
+		return arr, !prune || len(arr) > 0
+	case []any:
+		arr := make([]any, 0, len(v))
+		for i := range v {
+			if sub, keep := mapValues(appendPath(path, strconv.Itoa(i)), v[i], fn, prune); keep {
+				arr = append(arr, sub)
+			}
+		}
+		return arr, !prune || len(arr) > 0
+	default:
+	}
+	return value, true
+}
@@ -5,4 +5,5 @@ go 1.18
 require (
 	github.com/fatih/structs v1.1.0
 	github.com/mitchellh/mapstructure v1.1.2
+	gopkg.in/yaml.v3 v3.0.1
 )
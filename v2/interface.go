@@ -2,6 +2,7 @@ package extmap
 
 import (
 	"bytes"
+	"encoding/json"
 )
 
 // XMLer ...
@@ -28,6 +29,15 @@ func ParseNumber(v interface{}) (float64, bool) {
 		return v0, true
 	case float32:
 		return float64(v0), true
+	case int:
+		return float64(v0), true
+	case int32:
+		return float64(v0), true
+	case int64:
+		return float64(v0), true
+	case json.Number:
+		f, err := v0.Float64()
+		return f, err == nil
 	}
 	return 0, false
 }
@@ -51,6 +61,12 @@ func ParseInt(v interface{}) (int64, bool) {
 		return int64(v0), true
 	case float32:
 		return int64(v0), true
+	case json.Number:
+		if i, err := v0.Int64(); err == nil {
+			return i, true
+		}
+		f, err := v0.Float64()
+		return int64(f), err == nil
 	default:
 	}
 	return 0, false
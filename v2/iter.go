@@ -0,0 +1,102 @@
+//go:build go1.23
+
+package extmap
+
+import (
+	"iter"
+	"sort"
+	"strconv"
+)
+
+// All returns an iterator over the top level keys and values in random order like Range
+func (m *Map) All() iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		for k, v := range m.m {
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// Sorted returns an iterator over the top level keys and values in the order of SortKeys
+func (m *Map) Sorted() iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		for _, k := range m.SortKeys() {
+			if !yield(k, m.m[k]) {
+				return
+			}
+		}
+	}
+}
+
+// Leaves returns an iterator over the values which are not map or array with their dotted paths,
+// it recurses through *Map, map[string]any, []*Map and []any in sorted order,
+// the indexes of arrays are the path elements like "servers.0.host".
+// The empty maps and arrays are yielded as leaves like Walk and Flatten of gomap
+func (m *Map) Leaves() iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		leaves(yieldMap(m.m), "", yield)
+	}
+}
+
+func yieldMap(m map[string]any) func(func(string, any) bool) bool {
+	return func(fn func(string, any) bool) bool {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if !fn(k, m[k]) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// leaves yields the leaves of the children, it returns false if yield stops
+func leaves(children func(func(string, any) bool) bool, prefix string, yield func(string, any) bool) bool {
+	return children(func(k string, value any) bool {
+		path := prefix + k
+		switch v := value.(type) {
+		case *Map:
+			if v == nil || len(v.m) == 0 {
+				return yield(path, value)
+			}
+			return leaves(yieldMap(v.m), path+".", yield)
+		case map[string]any:
+			if len(v) == 0 {
+				return yield(path, value)
+			}
+			return leaves(yieldMap(v), path+".", yield)
+		case []*Map:
+			if len(v) == 0 {
+				return yield(path, value)
+			}
+			return leaves(func(fn func(string, any) bool) bool {
+				for i := range v {
+					if !fn(strconv.Itoa(i), v[i]) {
+						return false
+					}
+				}
+				return true
+			}, path+".", yield)
+		case []any:
+			if len(v) == 0 {
+				return yield(path, value)
+			}
+			return leaves(func(fn func(string, any) bool) bool {
+				for i := range v {
+					if !fn(strconv.Itoa(i), v[i]) {
+						return false
+					}
+				}
+				return true
+			}, path+".", yield)
+		default:
+		}
+		return yield(path, value)
+	})
+}
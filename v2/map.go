@@ -1,6 +1,7 @@
 package extmap
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"net/url"
@@ -36,8 +37,42 @@ type Map struct {
 	m       map[string]any
 }
 
-func (m Map) UnmarshalJSON(bytes []byte) error {
-	return json.Unmarshal(bytes, &m.m)
+func (m Map) UnmarshalJSON(data []byte) error {
+	if m.setting == nil || !m.setting.UseNumber && !m.setting.Int64 {
+		return json.Unmarshal(data, &m.m)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&m.m); err != nil {
+		return err
+	}
+	if m.setting.Int64 {
+		convertNumbers(m.m)
+	}
+	return nil
+}
+
+// convertNumbers replace the json.Number values with int64 or float64
+func convertNumbers(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+	case map[string]any:
+		for k := range v {
+			v[k] = convertNumbers(v[k])
+		}
+	case []any:
+		for i := range v {
+			v[i] = convertNumbers(v[i])
+		}
+	default:
+	}
+	return value
 }
 
 func (m Map) MarshalJSON() ([]byte, error) {
@@ -2,6 +2,10 @@ package extmap
 
 type Setting struct {
 	Split bool
+	// UseNumber decode the JSON numbers to json.Number instead of float64
+	UseNumber bool
+	// Int64 decode the integral JSON numbers to int64 and others to float64
+	Int64 bool
 }
 
 func defaultSetting() *Setting {
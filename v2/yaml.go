@@ -0,0 +1,79 @@
+package extmap
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MarshalYAML implements yaml.Marshaler
+func (m Map) MarshalYAML() (any, error) {
+	return m.m, nil
+}
+
+//ToYAML transfer map to YAML
+func (m *Map) ToYAML() ([]byte, error) {
+	return yaml.Marshal(m)
+}
+
+//ParseYAML parse YAML bytes to map, the nested mappings are converted to *Map,
+// the aliases are expanded to the anchored values
+func (m *Map) ParseYAML(b []byte) error {
+	var v map[string]any
+	if err := yaml.Unmarshal(b, &v); err != nil {
+		return fmt.Errorf("yaml to map error:%w", err)
+	}
+	for k := range v {
+		m.m[k] = m.convertYAML(v[k])
+	}
+	return nil
+}
+
+// ParseYAMLDocuments parse a multi-document YAML stream to maps, one *Map per document
+func ParseYAMLDocuments(b []byte, ss ...SettingOption) ([]*Map, error) {
+	var maps []*Map
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	for {
+		var v map[string]any
+		err := dec.Decode(&v)
+		if err == io.EOF {
+			return maps, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("yaml to map error:%w", err)
+		}
+		m := New(ss...)
+		for k := range v {
+			m.m[k] = m.convertYAML(v[k])
+		}
+		maps = append(maps, m)
+	}
+}
+
+// convertYAML converts the YAML mappings to *Map with the setting of m,
+// the non-string keys are formatted by fmt
+func (m *Map) convertYAML(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		sub := newWithSetting(m.setting)
+		for k := range v {
+			sub.m[k] = m.convertYAML(v[k])
+		}
+		return sub
+	case map[any]any:
+		sub := newWithSetting(m.setting)
+		for k := range v {
+			sub.m[fmt.Sprint(k)] = m.convertYAML(v[k])
+		}
+		return sub
+	case []any:
+		for i := range v {
+			v[i] = m.convertYAML(v[i])
+		}
+		return v
+	default:
+	}
+	return value
+}
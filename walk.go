@@ -0,0 +1,73 @@
+package gomap
+
+import (
+	"errors"
+	"strconv"
+)
+
+// ErrSkipSubtree is returned by the function of Walk to skip the children of the current map or array,
+// it is ignored for the other values
+var ErrSkipSubtree = errors.New("skip this subtree")
+
+// ErrStopWalk is returned by the function of Walk to stop walking, Walk returns nil for it
+var ErrStopWalk = errors.New("stop walking")
+
+// WalkFunc is called for every value of the tree, path is the keys from the root,
+// the indexes of arrays are the decimal strings
+type WalkFunc func(path []string, value interface{}) error
+
+// Walk call fn for every value in the tree of m in depth-first order, the keys are visited in sorted order
+// and the maps and arrays are visited before their children.
+// It recurses through Map, map[string]interface{}, []Map and []interface{},
+// and stops at the first error returned by fn except ErrSkipSubtree
+func (m Map) Walk(fn WalkFunc) error {
+	err := walkMap(nil, m, fn)
+	if errors.Is(err, ErrStopWalk) {
+		return nil
+	}
+	return err
+}
+
+func walkMap(path []string, m Map, fn WalkFunc) error {
+	for _, k := range m.SortKeys() {
+		if err := walkValue(appendPath(path, k), m[k], fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendPath always allocates, so the path passed to fn is never changed later
+func appendPath(path []string, k string) []string {
+	return append(path[:len(path):len(path)], k)
+}
+
+func walkValue(path []string, value interface{}, fn WalkFunc) error {
+	err := fn(path, value)
+	if errors.Is(err, ErrSkipSubtree) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case Map:
+		return walkMap(path, v, fn)
+	case map[string]interface{}:
+		return walkMap(path, v, fn)
+	case []Map:
+		for i := range v {
+			if err := walkValue(appendPath(path, strconv.Itoa(i)), v[i], fn); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i := range v {
+			if err := walkValue(appendPath(path, strconv.Itoa(i)), v[i], fn); err != nil {
+				return err
+			}
+		}
+	default:
+	}
+	return nil
+}
@@ -5,8 +5,8 @@ import (
 	"encoding/xml"
 	"errors"
 	"fmt"
-	"strconv"
 	"strings"
+	"time"
 )
 
 //CDATA xml cdata defines
@@ -15,7 +15,7 @@ type CDATA struct {
 	Value   string `xml:",cdata"`
 }
 
-func marshalXML(maps Map, e *xml.Encoder, start xml.StartElement) error {
+func marshalXML(maps Map, e *xml.Encoder, start xml.StartElement, path string, setting *XMLSetting) error {
 	if maps == nil {
 		return errors.New("map is nil")
 	}
@@ -24,7 +24,11 @@ func marshalXML(maps Map, e *xml.Encoder, start xml.StartElement) error {
 		return err
 	}
 	for k, v := range maps {
-		err := convertXML(k, v, e, xml.StartElement{Name: xml.Name{Local: k}})
+		key := k
+		if path != "" {
+			key = path + "." + k
+		}
+		err := convertXML(key, v, e, xml.StartElement{Name: setting.elementName(k)}, setting)
 		if err != nil {
 			return err
 		}
@@ -32,35 +36,42 @@ func marshalXML(maps Map, e *xml.Encoder, start xml.StartElement) error {
 	return e.EncodeToken(start.End())
 }
 
-func unmarshalXML(maps Map, d *xml.Decoder, start xml.StartElement, needCast bool) error {
+func unmarshalXML(maps Map, d *xml.Decoder, start xml.StartElement, setting *XMLSetting) error {
 	current := ""
+	var currentPath []string
 	var data interface{}
 	last := ""
 	arrayTmp := make(Map)
 	arrayTag := ""
+	var arrayPath []string
 	var ele []string
+	ns := newXMLNamespaces(setting)
 
 	for t, err := d.Token(); err == nil; t, err = d.Token() {
 		switch token := t.(type) {
 		case xml.StartElement:
+			ns.push(token)
 			if strings.ToLower(token.Name.Local) == "xml" ||
 				strings.ToLower(token.Name.Local) == "root" {
 				continue
 			}
-			ele = append(ele, token.Name.Local)
+			ele = append(ele, ns.key(token.Name))
 			current = strings.Join(ele, ".")
+			currentPath = append(currentPath[:0], ele...)
 			if current == last {
 				arrayTag = current
-				tmp := maps.Get(arrayTag)
+				arrayPath = append(arrayPath[:0], ele...)
+				tmp := maps.GetPath(arrayPath)
 				switch tmp.(type) {
 				case []interface{}:
-					arrayTmp.Set(arrayTag, tmp)
+					arrayTmp.SetPath(arrayPath, tmp)
 				default:
-					arrayTmp.Set(arrayTag, []interface{}{tmp})
+					arrayTmp.SetPath(arrayPath, []interface{}{tmp})
 				}
-				maps.Delete(arrayTag)
+				maps.DeletePath(arrayPath)
 			}
 		case xml.EndElement:
+			ns.pop()
 			name := token.Name.Local
 			if strings.ToLower(name) == "xml" ||
 				strings.ToLower(name) == "root" {
@@ -69,47 +80,30 @@ func unmarshalXML(maps Map, d *xml.Decoder, start xml.StartElement, needCast boo
 			last = strings.Join(ele, ".")
 			if current == last {
 				if data != nil {
-					maps.Set(current, data)
+					maps.SetPath(currentPath, data)
 				} else {
 				}
 				data = nil
 			}
 			if last == arrayTag {
-				arr := arrayTmp.GetArray(arrayTag)
+				arr, _ := arrayTmp.GetPath(arrayPath).([]interface{})
 				if arr != nil {
-					if v := maps.Get(arrayTag); v != nil {
-						maps.Set(arrayTag, append(arr, v))
+					if v := maps.GetPath(arrayPath); v != nil {
+						maps.SetPath(arrayPath, append(arr, v))
 					} else {
-						maps.Set(arrayTag, arr)
+						maps.SetPath(arrayPath, arr)
 					}
 				} else {
 					//exception doing
-					maps.Set(arrayTag, []interface{}{maps.Get(arrayTag)})
+					maps.SetPath(arrayPath, []interface{}{maps.GetPath(arrayPath)})
 				}
-				arrayTmp.Delete(arrayTag)
+				arrayTmp.DeletePath(arrayPath)
 				arrayTag = ""
 			}
 
 			ele = ele[:len(ele)-1]
 		case xml.CharData:
-			if needCast {
-				data, err = strconv.Atoi(string(token))
-				if err == nil {
-					continue
-				}
-
-				data, err = strconv.ParseFloat(string(token), 64)
-				if err == nil {
-					continue
-				}
-
-				data, err = strconv.ParseBool(string(token))
-				if err == nil {
-					continue
-				}
-			}
-
-			data = string(token)
+			data = setting.parseValue(current, string(token))
 		default:
 		}
 
@@ -117,55 +111,58 @@ func unmarshalXML(maps Map, d *xml.Decoder, start xml.StartElement, needCast boo
 
 	return nil
 }
-func convertXML(k string, v interface{}, e *xml.Encoder, start xml.StartElement) error {
+func convertXML(k string, v interface{}, e *xml.Encoder, start xml.StartElement, setting *XMLSetting) error {
 	var err error
 	switch v1 := v.(type) {
 	case Map:
-		return marshalXML(v1, e, xml.StartElement{Name: xml.Name{Local: k}})
+		return marshalXML(v1, e, start, k, setting)
 	case map[string]interface{}:
-		return marshalXML(v1, e, xml.StartElement{Name: xml.Name{Local: k}})
+		return marshalXML(v1, e, start, k, setting)
 	case string:
-		if _, err := strconv.ParseInt(v1, 10, 0); err != nil {
+		if setting.useCDATA(k, v1) {
 			err = e.EncodeElement(
-				CDATA{Value: v1}, xml.StartElement{Name: xml.Name{Local: k}})
+				CDATA{Value: v1}, start)
 			return err
 		}
-		err = e.EncodeElement(v1, xml.StartElement{Name: xml.Name{Local: k}})
+		err = e.EncodeElement(v1, start)
 		return err
 	case float64:
 		if v1 == float64(int64(v1)) {
-			err = e.EncodeElement(int64(v1), xml.StartElement{Name: xml.Name{Local: k}})
+			err = e.EncodeElement(int64(v1), start)
 			return err
 		}
-		err = e.EncodeElement(v1, xml.StartElement{Name: xml.Name{Local: k}})
+		err = e.EncodeElement(v1, start)
+		return err
+	case bool, float32, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		err = e.EncodeElement(v1, start)
 		return err
-	case bool:
-		err = e.EncodeElement(v1, xml.StartElement{Name: xml.Name{Local: k}})
+	case time.Time:
+		err = e.EncodeElement(v1.Format(setting.timeLayout()), start)
 		return err
 	case []interface{}:
 		size := len(v1)
 		for i := 0; i < size; i++ {
-			err := convertXML(k, v1[i], e, xml.StartElement{Name: xml.Name{Local: k}})
+			err := convertXML(k, v1[i], e, start, setting)
 			if err != nil {
 				return err
 			}
 		}
 		//add a null string to []
 		if size == 1 {
-			return convertXML(k, "", e, xml.StartElement{Name: xml.Name{Local: k}})
+			return convertXML(k, "", e, start, setting)
 		}
 	default:
 	}
 	return nil
 }
-func mapToXML(maps Map, needHeader bool) ([]byte, error) {
+func mapToXML(maps Map, needHeader bool, setting *XMLSetting) ([]byte, error) {
 	buff := bytes.NewBuffer([]byte(CustomHeader))
 	if needHeader {
 		buff.Write([]byte(xml.Header))
 	}
 
 	enc := xml.NewEncoder(buff)
-	err := marshalXML(maps, enc, xml.StartElement{Name: xml.Name{Local: "xml"}})
+	err := marshalXML(maps, enc, setting.rootElement(), "", setting)
 	if err != nil {
 		return nil, err
 	}
@@ -176,9 +173,9 @@ func mapToXML(maps Map, needHeader bool) ([]byte, error) {
 	return buff.Bytes(), nil
 }
 
-func xmlToMap(maps Map, contentXML []byte, hasHeader bool) error {
+func xmlToMap(maps Map, contentXML []byte, hasHeader bool, setting *XMLSetting) error {
 	dec := xml.NewDecoder(bytes.NewReader(contentXML))
-	err := unmarshalXML(maps, dec, xml.StartElement{Name: xml.Name{Local: "xml"}}, true)
+	err := unmarshalXML(maps, dec, xml.StartElement{Name: xml.Name{Local: "xml"}}, setting)
 	if err != nil {
 		return fmt.Errorf("xml to map error:%w", err)
 	}
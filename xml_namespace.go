@@ -0,0 +1,138 @@
+package gomap
+
+import (
+	"encoding/xml"
+	"sort"
+	"strings"
+)
+
+// XMLNamespace defines the key format of namespaced elements
+type XMLNamespace int
+
+const (
+	// XMLNamespaceNone use the local name as key, the namespace is dropped
+	XMLNamespaceNone XMLNamespace = iota
+	// XMLNamespacePrefix use "prefix:name" as key
+	XMLNamespacePrefix
+	// XMLNamespaceURI use "{uri}name" as key,
+	// the uri may contain '.', so use GetPath/SetPath to access these keys
+	XMLNamespaceURI
+)
+
+const xmlnsPrefix = "xmlns"
+
+// xmlNamespaces tracks the prefixes declared in the document
+type xmlNamespaces struct {
+	setting *XMLSetting
+	scopes  []map[string]string
+}
+
+func newXMLNamespaces(setting *XMLSetting) *xmlNamespaces {
+	return &xmlNamespaces{setting: setting}
+}
+
+func (n *xmlNamespaces) push(start xml.StartElement) {
+	var scope map[string]string
+	for _, attr := range start.Attr {
+		if attr.Name.Space == xmlnsPrefix {
+			if scope == nil {
+				scope = make(map[string]string)
+			}
+			scope[attr.Value] = attr.Name.Local
+		}
+	}
+	n.scopes = append(n.scopes, scope)
+}
+
+func (n *xmlNamespaces) pop() {
+	if len(n.scopes) > 0 {
+		n.scopes = n.scopes[:len(n.scopes)-1]
+	}
+}
+
+// prefix returns the preferred prefix of uri, or the innermost declared one
+func (n *xmlNamespaces) prefix(uri string) (string, bool) {
+	if p, b := n.setting.Prefixes[uri]; b {
+		return p, true
+	}
+	for i := len(n.scopes) - 1; i >= 0; i-- {
+		if p, b := n.scopes[i][uri]; b {
+			return p, true
+		}
+	}
+	return "", false
+}
+
+// inherit returns a copy of setting with the prefixes declared in the current scopes,
+// so a sub element can be decoded without its ancestors
+func (n *xmlNamespaces) inherit() *XMLSetting {
+	if n.setting.Namespace != XMLNamespacePrefix {
+		return n.setting
+	}
+	prefixes := make(map[string]string)
+	for _, scope := range n.scopes {
+		for uri, p := range scope {
+			prefixes[uri] = p
+		}
+	}
+	for uri, p := range n.setting.Prefixes {
+		prefixes[uri] = p
+	}
+	setting := *n.setting
+	setting.Prefixes = prefixes
+	return &setting
+}
+
+func (n *xmlNamespaces) key(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	switch n.setting.Namespace {
+	case XMLNamespacePrefix:
+		if p, b := n.prefix(name.Space); b && p != "" {
+			return p + ":" + name.Local
+		}
+	case XMLNamespaceURI:
+		return "{" + name.Space + "}" + name.Local
+	default:
+	}
+	return name.Local
+}
+
+// elementName converts the key to an element name,
+// "{uri}name" is written with the preferred prefix of uri if it exists
+func (s *XMLSetting) elementName(k string) xml.Name {
+	if strings.HasPrefix(k, "{") {
+		if i := strings.Index(k, "}"); i > 0 {
+			uri, local := k[1:i], k[i+1:]
+			if p, b := s.Prefixes[uri]; b && p != "" {
+				return xml.Name{Local: p + ":" + local}
+			}
+			return xml.Name{Space: uri, Local: local}
+		}
+	}
+	return xml.Name{Local: k}
+}
+
+// rootElement returns the root element with the namespace declarations of Prefixes
+func (s *XMLSetting) rootElement() xml.StartElement {
+	root := s.Root
+	if root == "" {
+		root = "xml"
+	}
+	start := xml.StartElement{Name: s.elementName(root)}
+	uris := make([]string, 0, len(s.Prefixes))
+	for uri := range s.Prefixes {
+		uris = append(uris, uri)
+	}
+	sort.Slice(uris, func(i, j int) bool {
+		return s.Prefixes[uris[i]] < s.Prefixes[uris[j]]
+	})
+	for _, uri := range uris {
+		start.Attr = append(start.Attr, xml.Attr{
+			Name:  xml.Name{Local: xmlnsPrefix + ":" + s.Prefixes[uri]},
+			Value: uri,
+		})
+	}
+	return start
+}
@@ -0,0 +1,219 @@
+package gomap
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// XMLType the value type of xml text
+type XMLType int
+
+const (
+	// XMLString keep the text as string
+	XMLString XMLType = iota
+	// XMLInt parse the text to int
+	XMLInt
+	// XMLFloat parse the text to float64
+	XMLFloat
+	// XMLBool parse the text to bool
+	XMLBool
+	// XMLTime parse the text to time.Time with XMLSetting.TimeLayout
+	XMLTime
+)
+
+// XMLCDATA the policy of writing string values in CDATA section
+type XMLCDATA int
+
+const (
+	// XMLCDATANotInt wrap the strings which are not integers
+	XMLCDATANotInt XMLCDATA = iota
+	// XMLCDATAAlways wrap all strings
+	XMLCDATAAlways
+	// XMLCDATANever never wrap strings, the markup characters are escaped
+	XMLCDATANever
+	// XMLCDATAMarkup wrap the strings which contain markup characters
+	XMLCDATAMarkup
+)
+
+// XMLSetting defines how the xml text is converted when parsing
+type XMLSetting struct {
+	// Cast infer the text to int, float64 or bool
+	Cast bool
+	// CastPaths restrict the inference to these paths, all paths are inferred if it is empty
+	CastPaths []string
+	// Schema defines the type of paths, it has priority over Cast
+	Schema map[string]XMLType
+	// TimeLayout is the layout of XMLTime values, default is time.RFC3339
+	TimeLayout string
+	// Namespace defines how the element namespace is kept in the key
+	Namespace XMLNamespace
+	// Prefixes maps the namespace uri to the preferred prefix,
+	// the prefixes are declared on the root element when marshaling
+	Prefixes map[string]string
+	// Root is the name of the root element when marshaling, default is "xml"
+	Root string
+	// CDATA defines when the string values are wrapped in CDATA section
+	CDATA XMLCDATA
+	// CDATAPaths defines the CDATA policy of paths, it has priority over CDATA
+	CDATAPaths map[string]XMLCDATA
+}
+
+// XMLOption ...
+type XMLOption func(op *XMLSetting)
+
+func defaultXMLSetting() *XMLSetting {
+	return &XMLSetting{
+		Cast:       true,
+		TimeLayout: time.RFC3339,
+		Root:       "xml",
+	}
+}
+
+// XMLCast enable or disable the type inference
+func XMLCast(b bool) XMLOption {
+	return func(op *XMLSetting) {
+		op.Cast = b
+	}
+}
+
+// XMLCastPaths restrict the type inference to paths
+func XMLCastPaths(paths ...string) XMLOption {
+	return func(op *XMLSetting) {
+		op.Cast = true
+		op.CastPaths = paths
+	}
+}
+
+// XMLSchema set the types of paths
+func XMLSchema(schema map[string]XMLType) XMLOption {
+	return func(op *XMLSetting) {
+		op.Schema = schema
+	}
+}
+
+// XMLTimeLayout set the layout of XMLTime values
+func XMLTimeLayout(layout string) XMLOption {
+	return func(op *XMLSetting) {
+		op.TimeLayout = layout
+	}
+}
+
+// XMLNamespaceKey set how the element namespace is kept in the key
+func XMLNamespaceKey(ns XMLNamespace) XMLOption {
+	return func(op *XMLSetting) {
+		op.Namespace = ns
+	}
+}
+
+// XMLPrefixes set the preferred prefixes of namespace uri
+func XMLPrefixes(prefixes map[string]string) XMLOption {
+	return func(op *XMLSetting) {
+		op.Prefixes = prefixes
+	}
+}
+
+// XMLRoot set the root element name when marshaling,
+// the name can be "prefix:name" or "{uri}name"
+func XMLRoot(name string) XMLOption {
+	return func(op *XMLSetting) {
+		op.Root = name
+	}
+}
+
+// XMLCDATAPolicy set the CDATA policy of string values
+func XMLCDATAPolicy(policy XMLCDATA) XMLOption {
+	return func(op *XMLSetting) {
+		op.CDATA = policy
+	}
+}
+
+// XMLCDATAPaths set the CDATA policy of paths
+func XMLCDATAPaths(paths map[string]XMLCDATA) XMLOption {
+	return func(op *XMLSetting) {
+		op.CDATAPaths = paths
+	}
+}
+
+func newXMLSetting(opts ...XMLOption) *XMLSetting {
+	setting := defaultXMLSetting()
+	for i := range opts {
+		opts[i](setting)
+	}
+	return setting
+}
+
+// parseValue convert the text of path, the text is returned if it can not be converted
+func (s *XMLSetting) parseValue(path string, text string) interface{} {
+	if t, b := s.Schema[path]; b {
+		if v, err := s.parseType(t, text); err == nil {
+			return v
+		}
+		return text
+	}
+	if !s.Cast || !s.castPath(path) {
+		return text
+	}
+	if v, err := strconv.Atoi(text); err == nil {
+		return v
+	}
+	if v, err := strconv.ParseFloat(text, 64); err == nil {
+		return v
+	}
+	if v, err := strconv.ParseBool(text); err == nil {
+		return v
+	}
+	return text
+}
+
+func (s *XMLSetting) castPath(path string) bool {
+	if len(s.CastPaths) == 0 {
+		return true
+	}
+	for i := range s.CastPaths {
+		if s.CastPaths[i] == path {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *XMLSetting) parseType(t XMLType, text string) (interface{}, error) {
+	switch t {
+	case XMLInt:
+		return strconv.Atoi(text)
+	case XMLFloat:
+		return strconv.ParseFloat(text, 64)
+	case XMLBool:
+		return strconv.ParseBool(text)
+	case XMLTime:
+		return time.Parse(s.timeLayout(), text)
+	default:
+	}
+	return text, nil
+}
+
+func (s *XMLSetting) timeLayout() string {
+	if s.TimeLayout == "" {
+		return time.RFC3339
+	}
+	return s.TimeLayout
+}
+
+func (s *XMLSetting) useCDATA(path string, text string) bool {
+	policy := s.CDATA
+	if p, b := s.CDATAPaths[path]; b {
+		policy = p
+	}
+	switch policy {
+	case XMLCDATAAlways:
+		return true
+	case XMLCDATANever:
+		return false
+	case XMLCDATAMarkup:
+		return strings.ContainsAny(text, "<>&")
+	default:
+	}
+	_, err := strconv.ParseInt(text, 10, 0)
+	return err != nil
+}
@@ -0,0 +1,135 @@
+package gomap
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrEmptyPath ...
+var ErrEmptyPath = errors.New("empty path")
+
+// DecodeXMLStream read XML from r and call fn with a Map for every element matching path,
+// the path is the dotted element names from the document root, e.g. "feed.entry",
+// the names are the keys of XMLNamespaceKey, such as "atom:feed.atom:entry" or "{uri}feed.{uri}entry".
+// Only the current element is kept in memory, so it can process very large documents.
+// If fn returns an error, decoding stops and the error is returned.
+// The paths of opts are relative to the matched element.
+func DecodeXMLStream(r io.Reader, path string, fn func(Map) error, opts ...XMLOption) error {
+	if path == "" {
+		return ErrEmptyPath
+	}
+	target := splitXMLPath(path)
+	setting := newXMLSetting(opts...)
+	dec := xml.NewDecoder(r)
+	ns := newXMLNamespaces(setting)
+	var ele []string
+	for {
+		t, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("xml stream error:%w", err)
+		}
+		switch token := t.(type) {
+		case xml.StartElement:
+			ns.push(token)
+			ele = append(ele, ns.key(token.Name))
+			if !matchPath(ele, target) {
+				continue
+			}
+			m := New()
+			sub := &subtreeReader{d: dec}
+			err := unmarshalXML(m, xml.NewTokenDecoder(sub), token, ns.inherit())
+			if err != nil {
+				return fmt.Errorf("xml stream error:%w", err)
+			}
+			if sub.err != nil {
+				return fmt.Errorf("xml stream error:%w", sub.err)
+			}
+			ns.pop()
+			ele = ele[:len(ele)-1]
+			if err := fn(m); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			ns.pop()
+			if len(ele) > 0 {
+				ele = ele[:len(ele)-1]
+			}
+		default:
+		}
+	}
+}
+
+// splitXMLPath split the dotted path, the dots inside "{uri}" are kept
+func splitXMLPath(path string) []string {
+	var keys []string
+	start, depth := 0, 0
+	for i := 0; i < len(path); i++ {
+		switch path[i] {
+		case '{':
+			depth++
+		case '}':
+			if depth > 0 {
+				depth--
+			}
+		case '.':
+			if depth == 0 {
+				keys = append(keys, path[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(keys, path[start:])
+}
+
+func matchPath(ele, target []string) bool {
+	if len(ele) != len(target) {
+		return false
+	}
+	for i := range ele {
+		if ele[i] != target[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// subtreeReader read the tokens inside the current element,
+// it returns io.EOF when the element is closed
+type subtreeReader struct {
+	d     *xml.Decoder
+	depth int
+	done  bool
+	err   error
+}
+
+// Token implements xml.TokenReader
+func (s *subtreeReader) Token() (xml.Token, error) {
+	if s.done {
+		return nil, io.EOF
+	}
+	t, err := s.d.Token()
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		s.err = err
+		s.done = true
+		return nil, err
+	}
+	switch t.(type) {
+	case xml.StartElement:
+		s.depth++
+	case xml.EndElement:
+		if s.depth == 0 {
+			s.done = true
+			return nil, io.EOF
+		}
+		s.depth--
+	}
+	return xml.CopyToken(t), nil
+}
@@ -0,0 +1,84 @@
+package gomap
+
+import (
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMap_UnmarshalXML(t *testing.T) {
+	doc := `<xml><id>007</id><n>1</n><name>bob</name></xml>`
+	want := Map{"id": "007", "n": "1", "name": "bob"}
+	got := New()
+	if err := xml.Unmarshal([]byte(doc), &got); err != nil {
+		t.Fatalf("xml.Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("xml.Unmarshal() = %v, want %v", got, want)
+	}
+	b, err := xml.Marshal(got)
+	if err != nil {
+		t.Fatalf("xml.Marshal() error = %v", err)
+	}
+	again := New()
+	if err := xml.Unmarshal(b, &again); err != nil {
+		t.Fatalf("xml.Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(again, want) {
+		t.Errorf("xml.Unmarshal(xml.Marshal()) = %v, want %v", again, want)
+	}
+}
+
+func TestMap_ToXML(t *testing.T) {
+	at := time.Date(2023, 10, 19, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		m    Map
+		want string
+	}{
+		{name: "int", m: Map{"a": 7}, want: "<a>7</a>"},
+		{name: "int64", m: Map{"a": int64(-7)}, want: "<a>-7</a>"},
+		{name: "uint64", m: Map{"a": uint64(18446744073709551615)}, want: "<a>18446744073709551615</a>"},
+		{name: "float32", m: Map{"a": float32(1.5)}, want: "<a>1.5</a>"},
+		{name: "float64", m: Map{"a": 2.0}, want: "<a>2</a>"},
+		{name: "bool", m: Map{"a": true}, want: "<a>true</a>"},
+		{name: "time", m: Map{"a": at}, want: "<a>2023-10-19T10:00:00Z</a>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.ToXML()
+			if err != nil {
+				t.Fatalf("ToXML() error = %v", err)
+			}
+			if !strings.Contains(string(got), tt.want) {
+				t.Errorf("ToXML() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMap_ParseXMLWith(t *testing.T) {
+	doc := `<xml><id>007</id><n>1</n><at>2023-10-19T10:00:00Z</at><name>bob</name></xml>`
+	opts := []XMLOption{XMLCast(false), XMLSchema(map[string]XMLType{"n": XMLInt, "at": XMLTime})}
+	want := Map{"id": "007", "n": 1, "at": time.Date(2023, 10, 19, 10, 0, 0, 0, time.UTC), "name": "bob"}
+	got := New()
+	if err := got.ParseXMLWith([]byte(doc), opts...); err != nil {
+		t.Fatalf("ParseXMLWith() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ParseXMLWith() = %v, want %v", got, want)
+	}
+	b, err := got.ToXMLWith(XMLCDATAPolicy(XMLCDATANever))
+	if err != nil {
+		t.Fatalf("ToXMLWith() error = %v", err)
+	}
+	again := New()
+	if err := again.ParseXMLWith(b, opts...); err != nil {
+		t.Fatalf("ParseXMLWith() error = %v", err)
+	}
+	if !reflect.DeepEqual(again, want) {
+		t.Errorf("ParseXMLWith(ToXMLWith()) = %v, want %v", again, want)
+	}
+}
@@ -0,0 +1,73 @@
+package gomap
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+//ToYAML transfer map to YAML
+func (m Map) ToYAML() ([]byte, error) {
+	return yaml.Marshal(m)
+}
+
+//ParseYAML parse YAML bytes to map, the nested mappings are converted to Map,
+// the aliases are expanded to the anchored values
+func (m Map) ParseYAML(b []byte) error {
+	var v map[string]interface{}
+	if err := yaml.Unmarshal(b, &v); err != nil {
+		return fmt.Errorf("yaml to map error:%w", err)
+	}
+	for k := range v {
+		m[k] = convertYAML(v[k])
+	}
+	return nil
+}
+
+// ParseYAMLDocuments parse a multi-document YAML stream to maps, one Map per document
+func ParseYAMLDocuments(b []byte) ([]Map, error) {
+	var maps []Map
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	for {
+		var v map[string]interface{}
+		err := dec.Decode(&v)
+		if err == io.EOF {
+			return maps, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("yaml to map error:%w", err)
+		}
+		m := New()
+		for k := range v {
+			m[k] = convertYAML(v[k])
+		}
+		maps = append(maps, m)
+	}
+}
+
+// convertYAML converts the YAML mappings to Map, the non-string keys are formatted by fmt
+func convertYAML(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		m := make(Map, len(v))
+		for k := range v {
+			m[k] = convertYAML(v[k])
+		}
+		return m
+	case map[interface{}]interface{}:
+		m := make(Map, len(v))
+		for k := range v {
+			m[fmt.Sprint(k)] = convertYAML(v[k])
+		}
+		return m
+	case []interface{}:
+		for i := range v {
+			v[i] = convertYAML(v[i])
+		}
+		return v
+	default:
+	}
+	return value
+}